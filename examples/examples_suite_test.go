@@ -6,6 +6,7 @@ import (
 
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
 	"github.com/onsi/gomega/gexec"
 )
 
@@ -45,6 +46,47 @@ var _ = Describe("Examples", func() {
 		})
 	})
 
+	Context("the web with workers", func() {
+		It("works", func() {
+			path, err := gexec.Build("github.com/jtarchie/dothings/examples/web", "-race")
+			Expect(err).NotTo(HaveOccurred())
+
+			command := exec.Command(path, "-duration", "100ms", "-polling-interval", "100ms", "-num-tasks", "4", "-port", "18081", "-dispatch")
+			session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(session.Err, "5s").Should(gbytes.Say("listening on"))
+
+			workers := []*gexec.Session{}
+			for i := 0; i < 2; i++ {
+				command := exec.Command(path, "-duration", "100ms", "-polling-interval", "100ms", "-num-tasks", "4", "-worker", "http://localhost:18081/workers", "-capacity", "2")
+				worker, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+				Expect(err).NotTo(HaveOccurred())
+				workers = append(workers, worker)
+			}
+
+			Eventually(session, "10s").Should(gexec.Exit(0))
+			for _, worker := range workers {
+				worker.Kill()
+			}
+		})
+	})
+
+	Context("the web when interrupted", func() {
+		It("aborts the build and exits with its status", func() {
+			path, err := gexec.Build("github.com/jtarchie/dothings/examples/web", "-race")
+			Expect(err).NotTo(HaveOccurred())
+
+			command := exec.Command(path, "-duration", "1m", "-polling-interval", "100ms", "-num-tasks", "2", "-port", "18082")
+			session, err := gexec.Start(command, GinkgoWriter, GinkgoWriter)
+			Expect(err).NotTo(HaveOccurred())
+			Eventually(session.Err, "5s").Should(gbytes.Say("running -> success|unstarted -> running"))
+
+			session.Interrupt()
+			Eventually(session.Err, "5s").Should(gbytes.Say("aborting the build"))
+			Eventually(session, "5s").Should(gexec.Exit(1))
+		})
+	})
+
 	Context("the pipeline", func() {
 		It("works", func() {
 			_, err := gexec.Build("github.com/jtarchie/dothings/examples/pipeline", "-race")
@@ -52,4 +94,11 @@ var _ = Describe("Examples", func() {
 			// not running yet, just want a compiler check
 		})
 	})
+
+	Context("the replay", func() {
+		It("works", func() {
+			_, err := gexec.Build("github.com/jtarchie/dothings/examples/replay", "-race")
+			Expect(err).NotTo(HaveOccurred())
+		})
+	})
 })
@@ -1,17 +1,27 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"github.com/jtarchie/dothings/examples/pipeline/steps/managers/docker"
 	"io/ioutil"
 	"log"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/jtarchie/dothings/examples/pipeline/models"
 	"github.com/jtarchie/dothings/examples/pipeline/steps"
 	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/executor/cache"
+	"github.com/jtarchie/dothings/executor/remote"
 	"github.com/jtarchie/dothings/executor/writers"
+	"github.com/jtarchie/dothings/journal"
+	"github.com/jtarchie/dothings/reports"
 	"github.com/jtarchie/dothings/status"
 	"gopkg.in/yaml.v2"
 )
@@ -19,6 +29,15 @@ import (
 func main() {
 	configFile := flag.String("config", "", "pipeline to configure")
 	port := flag.Int("port", 8080, "port of the http server")
+	graph := flag.String("graph", "", "print the plan as 'dot' or 'mermaid' and exit")
+	workers := flag.Int("workers", 0, "maximum number of tasks to run at once, unlimited when 0")
+	journalFile := flag.String("journal", "", "append every build event to this file for replaying later")
+	cacheDir := flag.String("cache", "", "reuse the results of tasks with matching inputs from this directory")
+	grace := flag.Duration("grace", 30*time.Second, "time for hooks to run after an interrupt before exiting")
+	dispatch := flag.Bool("dispatch", false, "run tasks on workers registered at /workers instead of locally")
+	workFor := flag.String("worker", "", "run as a worker for the coordinator at this url")
+	tags := flag.String("tags", "", "comma separated tags offered when running as a worker")
+	capacity := flag.Int("capacity", 1, "number of tasks to run at once when running as a worker")
 	flag.Parse()
 
 	contents, err := ioutil.ReadFile(*configFile)
@@ -36,25 +55,149 @@ func main() {
 		log.Fatalf("could not unmarshal pipeline from config file: %s", err)
 	}
 
-	builder := steps.NewBuilder(pipeline, docker.NewFactory())
+	factory := docker.NewFactory()
+	builder := steps.NewBuilder(pipeline, factory)
 	plan, err := builder.PlanForJob(pipeline.Jobs[0].Name)
 	if err != nil {
 		log.Fatalf("could not build plan for pipeline: %s", err)
 	}
 
-	log.Println("starting execution")
-	inMemory := writers.NewInMemory()
-	statuses := status.NewStatuses()
-	handler := writers.NewWebHandler(plan, inMemory, statuses)
+	switch *graph {
+	case "":
+	case "dot":
+		err = reports.NewDot(plan, nil).Write(os.Stdout)
+		if err != nil {
+			log.Fatalf("could not write graph: %s", err)
+		}
+		return
+	case "mermaid":
+		err = reports.NewMermaid(plan, nil).Write(os.Stdout)
+		if err != nil {
+			log.Fatalf("could not write graph: %s", err)
+		}
+		return
+	default:
+		log.Fatalf("unknown graph format '%s'", *graph)
+	}
 
-	http.Handle("/", handler)
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	if *workFor != "" {
+		offered := []string{}
+		if *tags != "" {
+			offered = strings.Split(*tags, ",")
+		}
+		hostname, _ := os.Hostname()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			received := <-signals
+			log.Printf("received %s, stopping the worker", received)
+			cancel()
+		}()
+
+		log.Printf("working for %s", *workFor)
+		worker := remote.NewWorker(*workFor, hostname, offered, *capacity, remote.NewPlanResolver(plan))
+		err = worker.Run(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
 
-	go executor.NewExecutorWithStater(
+	log.Println("starting execution")
+	var inMemory executor.Writer = writers.NewInMemory()
+	var statuses status.Stater = status.NewStatuses()
+	var file *os.File
+	if *journalFile != "" {
+		file, err = os.OpenFile(*journalFile, os.O_APPEND|os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err != nil {
+			log.Fatalf("could not open journal: %s", err)
+		}
+
+		recorder := journal.NewJournal(file, plan, statuses, inMemory)
+		inMemory, statuses = recorder, recorder
+	}
+	var results executor.Cache
+	if *cacheDir != "" {
+		results, err = cache.NewDirectory(*cacheDir)
+		if err != nil {
+			log.Fatalf("could not open cache: %s", err)
+		}
+	}
+	var dispatcher executor.Dispatcher
+	if *dispatch {
+		coordinator := remote.NewCoordinator()
+		http.Handle("/workers/", http.StripPrefix("/workers", coordinator))
+		dispatcher = coordinator
+	}
+	execution := executor.NewExecutorWithStater(
 		plan,
 		inMemory,
 		statuses,
-	).Wait()
+		executor.WithWorkers(*workers),
+		executor.WithCache(results),
+		executor.WithDispatcher(dispatcher),
+	)
+	handler := writers.NewWebHandlerWithApprover(plan, inMemory, statuses, execution)
+
+	http.Handle("/", handler)
 
+	finished := make(chan status.Type, 1)
+	go func() {
+		finished <- execution.Wait()
+	}()
+
+	server := &http.Server{Addr: fmt.Sprintf(":%d", *port)}
+	go func() {
+		err := server.ListenAndServe()
+		if err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
 	log.Printf("listening on http://localhost:%d", *port)
-	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", *port), nil))
+
+	var finalState status.Type
+	select {
+	case finalState = <-finished:
+		log.Printf("build finished with %s, serving results until interrupted", finalState)
+		<-signals
+	case received := <-signals:
+		log.Printf("received %s, aborting the build", received)
+		execution.Abort()
+
+		select {
+		case finalState = <-finished:
+		case <-time.After(*grace):
+			log.Printf("build did not stop within %s", *grace)
+			finalState = status.Errored
+		case <-signals:
+			log.Println("received a second signal, exiting immediately")
+			finalState = status.Errored
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = server.Shutdown(ctx)
+	if err != nil {
+		log.Printf("could not stop http server: %s", err)
+	}
+
+	if file != nil {
+		err = file.Sync()
+		if err != nil {
+			log.Printf("could not flush journal: %s", err)
+		}
+		_ = file.Close()
+	}
+
+	err = factory.Destroy()
+	if err != nil {
+		log.Printf("could not clean up volumes: %s", err)
+	}
+
+	log.Printf("exiting with %s", finalState)
+	os.Exit(finalState.ExitCode())
 }
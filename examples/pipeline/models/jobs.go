@@ -2,6 +2,7 @@ package models
 
 import (
 	"reflect"
+	"time"
 )
 
 type input struct {
@@ -43,8 +44,26 @@ type get struct {
 	Name string `yaml:"get"`
 }
 
+type loadVar struct {
+	Name   string `yaml:"load_var"`
+	File   string
+	Format string
+}
+
+type approve struct {
+	Name    string        `yaml:"approve"`
+	Timeout time.Duration `yaml:"approval_timeout"`
+}
+
 type stepParams map[string]interface{}
 
+type acrossVar struct {
+	Var    string
+	Values []string
+}
+
+type acrossVars []acrossVar
+
 type put struct {
 	Name      string `yaml:"put"`
 	GetParams stepParams
@@ -54,9 +73,12 @@ type Step struct {
 	Task       task       `yaml:",inline"`
 	Get        get        `yaml:",inline"`
 	Put        put        `yaml:",inline"`
+	LoadVar    loadVar    `yaml:",inline"`
+	Approve    approve    `yaml:",inline"`
 	InParallel Steps      `yaml:"in_parallel"`
 	Do         Steps      `yaml:"do"`
 	Params     stepParams `yaml:"params"`
+	Across     acrossVars `yaml:"across"`
 	Tags       []string
 	Attempts   int
 }
@@ -75,6 +97,10 @@ func (t Type) String() string {
 		return "InParallel"
 	case Do:
 		return "Do"
+	case LoadVar:
+		return "LoadVar"
+	case Approve:
+		return "Approve"
 	}
 	return "Unknown"
 }
@@ -85,6 +111,8 @@ const (
 	Put
 	InParallel
 	Do
+	LoadVar
+	Approve
 	Unknown
 )
 
@@ -98,6 +126,12 @@ func (step Step) Type() Type {
 	if !reflect.DeepEqual(step.Put, put{}) {
 		return Put
 	}
+	if !reflect.DeepEqual(step.LoadVar, loadVar{}) {
+		return LoadVar
+	}
+	if !reflect.DeepEqual(step.Approve, approve{}) {
+		return Approve
+	}
 	if step.InParallel != nil {
 		return InParallel
 	}
@@ -110,8 +144,9 @@ func (step Step) Type() Type {
 type Steps []Step
 
 type Job struct {
-	Name  string
-	Steps Steps `yaml:"plan"`
+	Name         string
+	Steps        Steps    `yaml:"plan"`
+	SerialGroups []string `yaml:"serial_groups"`
 }
 
 type Jobs []Job
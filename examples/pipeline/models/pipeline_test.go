@@ -1,6 +1,8 @@
 package models_test
 
 import (
+	"time"
+
 	. "github.com/jtarchie/dothings/examples/pipeline/models"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -16,4 +18,30 @@ var _ = Describe("Parsing a pipeline from YAML", func() {
 		Expect(len(pipeline.Resources)).To(BeNumerically(">=", 1))
 		Expect(len(pipeline.Jobs)).To(BeNumerically(">=", 1))
 	})
+
+	It("handles parsing load_var steps", func() {
+		var step Step
+		err := yaml.UnmarshalStrict([]byte(`
+load_var: version
+file: artifact/version
+format: raw
+`), &step)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(step.Type()).To(Equal(LoadVar))
+		Expect(step.LoadVar.Name).To(Equal("version"))
+		Expect(step.LoadVar.File).To(Equal("artifact/version"))
+		Expect(step.LoadVar.Format).To(Equal("raw"))
+	})
+
+	It("handles parsing approve steps", func() {
+		var step Step
+		err := yaml.UnmarshalStrict([]byte(`
+approve: production
+approval_timeout: 1h
+`), &step)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(step.Type()).To(Equal(Approve))
+		Expect(step.Approve.Name).To(Equal("production"))
+		Expect(step.Approve.Timeout).To(Equal(time.Hour))
+	})
 })
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+func (a acrossVars) Axes() map[string][]string {
+	axes := map[string][]string{}
+	for _, v := range a {
+		axes[v.Var] = v.Values
+	}
+	return axes
+}
+
+func (step Step) Interpolate(vars map[string]string) Step {
+	pairs := []string{}
+	for name, value := range vars {
+		pairs = append(pairs, fmt.Sprintf("((.:%s))", name), value)
+	}
+	replacer := strings.NewReplacer(pairs...)
+
+	return interpolate(reflect.ValueOf(step), replacer).Interface().(Step)
+}
+
+func interpolate(value reflect.Value, replacer *strings.Replacer) reflect.Value {
+	switch value.Kind() {
+	case reflect.String:
+		return reflect.ValueOf(replacer.Replace(value.String())).Convert(value.Type())
+	case reflect.Interface:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.New(value.Type()).Elem()
+		copied.Set(interpolate(value.Elem(), replacer))
+		return copied
+	case reflect.Struct:
+		copied := reflect.New(value.Type()).Elem()
+		for i := 0; i < value.NumField(); i++ {
+			copied.Field(i).Set(interpolate(value.Field(i), replacer))
+		}
+		return copied
+	case reflect.Slice:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeSlice(value.Type(), value.Len(), value.Len())
+		for i := 0; i < value.Len(); i++ {
+			copied.Index(i).Set(interpolate(value.Index(i), replacer))
+		}
+		return copied
+	case reflect.Map:
+		if value.IsNil() {
+			return value
+		}
+		copied := reflect.MakeMapWithSize(value.Type(), value.Len())
+		for _, key := range value.MapKeys() {
+			copied.SetMapIndex(key, interpolate(value.MapIndex(key), replacer))
+		}
+		return copied
+	}
+	return value
+}
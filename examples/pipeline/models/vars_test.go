@@ -0,0 +1,55 @@
+package models_test
+
+import (
+	. "github.com/jtarchie/dothings/examples/pipeline/models"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+var _ = Describe("Step variables", func() {
+	var step Step
+
+	BeforeEach(func() {
+		step = Step{}
+		err := yaml.UnmarshalStrict([]byte(`
+task: test-((.:go))
+across:
+- var: go
+  values: ["1.12", "1.13"]
+- var: os
+  values: [linux]
+config:
+  image_resource:
+    source:
+      repository: golang
+      tag: ((.:go))
+  params:
+    GOOS: ((.:os))
+  run:
+    path: go
+    args: [test, ((.:unknown))]
+`), &step)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("parses across into axes", func() {
+		Expect(step.Type()).To(Equal(Task))
+		Expect(step.Across.Axes()).To(Equal(map[string][]string{
+			"go": {"1.12", "1.13"},
+			"os": {"linux"},
+		}))
+	})
+
+	It("interpolates local vars without changing the original", func() {
+		interpolated := step.Interpolate(map[string]string{"go": "1.13", "os": "linux"})
+
+		Expect(interpolated.Task.Name).To(Equal("test-1.13"))
+		Expect(interpolated.Task.Config.ImageResource.Source["tag"]).To(Equal("1.13"))
+		Expect(interpolated.Task.Config.Params["GOOS"]).To(Equal("linux"))
+		Expect(interpolated.Task.Config.Run.Args).To(Equal([]string{"test", "((.:unknown))"}))
+
+		Expect(step.Task.Name).To(Equal("test-((.:go))"))
+		Expect(step.Task.Config.Params["GOOS"]).To(Equal("((.:os))"))
+	})
+})
@@ -5,12 +5,15 @@ import (
 	"github.com/jtarchie/dothings/examples/pipeline/models"
 	"github.com/jtarchie/dothings/examples/pipeline/steps/managers"
 	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/tasks"
 )
 
 type builder struct {
 	pipeline       *models.Pipeline
 	versionManager versionManager
 	factory        factory
+	vars           localVars
+	sequence       int
 }
 
 func NewBuilder(pipeline *models.Pipeline, factory factory) *builder {
@@ -27,6 +30,8 @@ func (b *builder) PlanForJob(jobName string) (planner.Step, error) {
 	if job == nil {
 		return nil, fmt.Errorf("job '%s' not found", jobName)
 	}
+	b.vars = localVars{}
+	b.sequence = 0
 
 	return planner.NewSerial(func(plan planner.Planner) error {
 		err := b.createPlanFromSteps(plan, job.Steps)
@@ -34,11 +39,19 @@ func (b *builder) PlanForJob(jobName string) (planner.Step, error) {
 			return fmt.Errorf("with job '%s': %s", jobName, err)
 		}
 		return nil
-	})
+	}, planner.WithValidation(), planner.WithLocks(job.SerialGroups...))
 }
 
 func (b *builder) createPlanFromSteps(plan planner.Planner, steps models.Steps) error {
 	for _, step := range steps {
+		if len(step.Across) > 0 {
+			err := b.setupAcross(step, plan)
+			if err != nil {
+				return fmt.Errorf("across not buildable: %s", err)
+			}
+			continue
+		}
+
 		switch step.Type() {
 		case models.Get:
 			err := b.setupGet(step, plan)
@@ -47,6 +60,10 @@ func (b *builder) createPlanFromSteps(plan planner.Planner, steps models.Steps)
 			}
 		case models.Task:
 			b.setupTask(plan, step)
+		case models.LoadVar:
+			b.setupLoadVar(plan, step)
+		case models.Approve:
+			plan.Task(tasks.NewApproval(step.Approve.Name, step.Approve.Timeout))
 		case models.Put:
 			err := b.setupPut(step, plan)
 			if err != nil {
@@ -73,6 +90,15 @@ func (b *builder) createPlanFromSteps(plan planner.Planner, steps models.Steps)
 	return nil
 }
 
+func (b *builder) setupAcross(step models.Step, plan planner.Planner) error {
+	axes := step.Across.Axes()
+	step.Across = nil
+
+	return plan.Matrix(axes, func(plan planner.Planner, vars map[string]string) error {
+		return b.createPlanFromSteps(plan, models.Steps{step.Interpolate(vars)})
+	})
+}
+
 func (b *builder) setupPut(step models.Step, plan planner.Planner) error {
 	resourceName := step.Put.Name
 	resource := b.pipeline.Resources.FindByName(resourceName)
@@ -80,30 +106,52 @@ func (b *builder) setupPut(step models.Step, plan planner.Planner) error {
 		return fmt.Errorf("resource '%s' not found for put", resourceName)
 	}
 	return plan.Serial(func(plan planner.Planner) error {
-		plan.Task(NewPutResource(
+		put := NewPutResource(
 			resource,
 			b.versionManager,
 			b.factory.VolumeManager(),
 			b.factory.NewContainerManager(),
 			step.Params,
-		))
-		plan.Task(NewGetResource(
+		)
+		put.sequence = b.next()
+		plan.Task(put)
+
+		get := NewGetResource(
 			resource,
 			b.versionManager,
 			b.factory.VolumeManager(),
 			b.factory.NewContainerManager(),
 			step.Put.GetParams,
-		))
+		)
+		get.sequence = b.next()
+		plan.Task(get)
 		return nil
 	})
 }
 
 func (b *builder) setupTask(plan planner.Planner, step models.Step) {
-	plan.Task(NewTask(
+	task := NewTask(
 		step,
 		b.factory.VolumeManager(),
 		b.factory.NewContainerManager(),
-	))
+	)
+	task.sequence = b.next()
+	task.vars = localVars{}
+	for name, loader := range b.vars {
+		task.vars[name] = loader
+	}
+	plan.Task(task)
+}
+
+func (b *builder) setupLoadVar(plan planner.Planner, step models.Step) {
+	loader := NewLoadVar(
+		step,
+		b.factory.VolumeManager(),
+		b.factory.NewContainerManager(),
+	)
+	loader.sequence = b.next()
+	b.vars[step.LoadVar.Name] = loader
+	plan.Task(loader)
 }
 
 func (b *builder) setupGet(step models.Step, plan planner.Planner) error {
@@ -113,18 +161,28 @@ func (b *builder) setupGet(step models.Step, plan planner.Planner) error {
 		return fmt.Errorf("resource '%s' not found for get", resourceName)
 	}
 	return plan.Serial(func(plan planner.Planner) error {
-		plan.Task(NewCheckResource(
+		check := NewCheckResource(
 			resource,
 			b.versionManager,
 			b.factory.NewContainerManager(),
-		))
-		plan.Task(NewGetResource(
+		)
+		check.sequence = b.next()
+		plan.Task(check)
+
+		get := NewGetResource(
 			resource,
 			b.versionManager,
 			b.factory.VolumeManager(),
 			b.factory.NewContainerManager(),
 			step.Params,
-		))
+		)
+		get.sequence = b.next()
+		plan.Task(get)
 		return nil
 	})
 }
+
+func (b *builder) next() int {
+	b.sequence++
+	return b.sequence
+}
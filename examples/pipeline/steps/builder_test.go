@@ -0,0 +1,179 @@
+package steps_test
+
+import (
+	"io"
+	"time"
+
+	"github.com/jtarchie/dothings/examples/pipeline/models"
+	"github.com/jtarchie/dothings/examples/pipeline/steps"
+	"github.com/jtarchie/dothings/examples/pipeline/steps/stepsfakes"
+	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/executor/writers"
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"gopkg.in/yaml.v2"
+)
+
+type fakeFactory struct{}
+
+func (fakeFactory) VolumeManager() steps.VolumeManager {
+	return &stepsfakes.FakeVolumeManager{}
+}
+
+func (fakeFactory) NewContainerManager() steps.ContainerManager {
+	return &stepsfakes.FakeContainerManager{}
+}
+
+type recordingFactory struct {
+	containers []*stepsfakes.FakeContainerManager
+}
+
+func (*recordingFactory) VolumeManager() steps.VolumeManager {
+	return &stepsfakes.FakeVolumeManager{}
+}
+
+func (f *recordingFactory) NewContainerManager() steps.ContainerManager {
+	container := &stepsfakes.FakeContainerManager{}
+	f.containers = append(f.containers, container)
+	return container
+}
+
+func newBuilder(config string) interface {
+	PlanForJob(string) (planner.Step, error)
+} {
+	pipeline := &models.Pipeline{}
+	err := yaml.UnmarshalStrict([]byte(config), pipeline)
+	Expect(err).NotTo(HaveOccurred())
+
+	return steps.NewBuilder(pipeline, fakeFactory{})
+}
+
+var _ = Describe("Builder", func() {
+	It("expands across steps into a matrix", func() {
+		builder := newBuilder(`
+jobs:
+- name: test
+  plan:
+  - task: test-((.:go))
+    across:
+    - var: go
+      values: ["1.12", "1.13"]
+    config:
+      image_resource:
+        source:
+          repository: golang
+      run:
+        path: go
+`)
+		plan, err := builder.PlanForJob("test")
+		Expect(err).NotTo(HaveOccurred())
+
+		matrix := plan.Tree().Children()[0]
+		Expect(matrix.Type()).To(Equal(planner.Parallel))
+		Expect(matrix.Children()).To(HaveLen(2))
+		Expect(matrix.Children()[0].Children()[0].Task().ID()).To(MatchRegexp(`^task: test-1.12 \(\d+\) \[go=1.12\]$`))
+		Expect(matrix.Children()[1].Children()[0].Task().ID()).To(MatchRegexp(`^task: test-1.13 \(\d+\) \[go=1.13\]$`))
+	})
+
+	It("numbers steps the same way every time the plan is built", func() {
+		config := `
+jobs:
+- name: test
+  plan:
+  - task: build
+    config:
+      run:
+        path: make
+  - task: build
+    config:
+      run:
+        path: make
+`
+		ids := func() []string {
+			plan, err := newBuilder(config).PlanForJob("test")
+			Expect(err).NotTo(HaveOccurred())
+
+			ids := []string{}
+			for _, child := range plan.Tree().Children() {
+				ids = append(ids, child.Task().ID())
+			}
+			return ids
+		}
+
+		Expect(ids()).To(Equal([]string{"task: build (1)", "task: build (2)"}))
+		Expect(ids()).To(Equal([]string{"task: build (1)", "task: build (2)"}))
+	})
+
+	It("interpolates loaded vars into later tasks", func() {
+		pipeline := &models.Pipeline{}
+		err := yaml.UnmarshalStrict([]byte(`
+jobs:
+- name: test
+  plan:
+  - load_var: version
+    file: artifact/version
+  - task: release
+    config:
+      image_resource:
+        source:
+          repository: ubuntu
+      run:
+        path: echo
+        args: ["v((.:version))"]
+`), pipeline)
+		Expect(err).NotTo(HaveOccurred())
+
+		factory := &recordingFactory{}
+		plan, err := steps.NewBuilder(pipeline, factory).PlanForJob("test")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(factory.containers).To(HaveLen(2))
+
+		factory.containers[0].RunStub = func(stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+			_, _ = io.WriteString(stdout, "1.2.3\n")
+			return nil
+		}
+
+		inMemory := writers.NewInMemory()
+		Expect(executor.NewExecutor(plan, inMemory).Wait()).To(Equal(status.Success))
+
+		_, args := factory.containers[1].CommandArgsForCall(0)
+		Expect(args).To(Equal([]string{"v1.2.3"}))
+	})
+
+	It("locks the job with its serial groups", func() {
+		builder := newBuilder(`
+jobs:
+- name: test
+  serial_groups: [staging, database]
+  plan:
+  - task: deploy
+    config:
+      image_resource:
+        source:
+          repository: ubuntu
+      run:
+        path: deploy
+`)
+		plan, err := builder.PlanForJob("test")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(plan.Tree().Locks()).To(Equal([]string{"staging", "database"}))
+	})
+
+	It("adds approval gates", func() {
+		builder := newBuilder(`
+jobs:
+- name: test
+  plan:
+  - approve: production
+    approval_timeout: 30m
+`)
+		plan, err := builder.PlanForJob("test")
+		Expect(err).NotTo(HaveOccurred())
+
+		gate := plan.Tree().Children()[0].Task()
+		Expect(gate.ID()).To(Equal("approval: production"))
+		Expect(gate.(executor.GateTasker).ApprovalTimeout()).To(Equal(30 * time.Minute))
+	})
+})
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
-	"time"
 
 	"github.com/jtarchie/dothings/examples/pipeline/models"
 	"github.com/jtarchie/dothings/planner"
@@ -16,7 +15,7 @@ import (
 type CheckResource struct {
 	resource         *models.Resource
 	versionManager   versionManager
-	timestamp        int64
+	sequence         int
 	containerManager ContainerManager
 }
 
@@ -29,12 +28,11 @@ func NewCheckResource(
 		resource:         r,
 		versionManager:   version,
 		containerManager: container,
-		timestamp:        time.Now().UnixNano(),
 	}
 }
 
 func (c *CheckResource) ID() string {
-	return fmt.Sprintf("check resource: %s (%d)", c.resource.Name, c.timestamp)
+	return fmt.Sprintf("check resource: %s (%d)", c.resource.Name, c.sequence)
 }
 
 func (c *CheckResource) Execute(stdout io.Writer, stderr io.Writer) (status.Type, error) {
@@ -70,7 +68,7 @@ func (c *CheckResource) Execute(stdout io.Writer, stderr io.Writer) (status.Type
 
 	if err != nil {
 		if _, ok := err.(*exec.ExitError); ok {
-			return status.Failed, nil
+			return status.Failed, err
 		}
 		return status.Errored, fmt.Errorf("check resource execute errored: %s", err)
 	}
@@ -148,7 +148,7 @@ var _ = Describe("CheckResource", func() {
 				}
 
 				s, err := check.Execute(ioutil.Discard, ioutil.Discard)
-				Expect(err).ToNot(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(&exec.ExitError{}))
 				Expect(s).To(Equal(status.Failed))
 			})
 
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"io"
 	"os/exec"
-	"time"
 
 	"github.com/jtarchie/dothings/examples/pipeline/models"
 	"github.com/jtarchie/dothings/planner"
@@ -19,7 +18,7 @@ type GetResource struct {
 	volumeManager    VolumeManager
 	containerManager ContainerManager
 	params           map[string]interface{}
-	timestamp        int64
+	sequence         int
 }
 
 func NewGetResource(
@@ -35,12 +34,11 @@ func NewGetResource(
 		volumeManager:    volumeManager,
 		containerManager: containerManger,
 		params:           params,
-		timestamp:        time.Now().UnixNano(),
 	}
 }
 
 func (g *GetResource) ID() string {
-	return fmt.Sprintf("get resource: %s (%d)", g.resource.Name, g.timestamp)
+	return fmt.Sprintf("get resource: %s (%d)", g.resource.Name, g.sequence)
 }
 
 func (g *GetResource) Execute(stdout io.Writer, stderr io.Writer) (status.Type, error) {
@@ -81,7 +79,7 @@ func (g *GetResource) Execute(stdout io.Writer, stderr io.Writer) (status.Type,
 
 	if err != nil {
 		if _, ok := err.(*exec.ExitError); ok {
-			return status.Failed, nil
+			return status.Failed, err
 		}
 		return status.Errored, fmt.Errorf("get resource execute errored: %s", err)
 	}
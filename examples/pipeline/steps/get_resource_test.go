@@ -111,7 +111,7 @@ var _ = Describe("GetResource", func() {
 				}
 
 				s, err := get.Execute(ioutil.Discard, ioutil.Discard)
-				Expect(err).ToNot(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(&exec.ExitError{}))
 				Expect(s).To(Equal(status.Failed))
 			})
 
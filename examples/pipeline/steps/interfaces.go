@@ -1,6 +1,7 @@
 package steps
 
 import (
+	"context"
 	"io"
 
 	"github.com/jtarchie/dothings/examples/pipeline/models"
@@ -19,6 +20,8 @@ type versionManager interface {
 type VolumeManager interface {
 	Get(string, bool) string
 	All() map[string]string
+	Export(name string, w io.Writer) error
+	Import(name string, r io.Reader) error
 }
 
 //counterfeiter:generate . ContainerManager
@@ -31,6 +34,7 @@ type ContainerManager interface {
 	EnvVar(name string, value string)
 	Privileged(bool)
 	User(string)
+	Context(context.Context)
 	Run(
 		stdin io.Reader,
 		stdout io.Writer,
@@ -0,0 +1,106 @@
+package steps
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+
+	"github.com/jtarchie/dothings/examples/pipeline/models"
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+type LoadVar struct {
+	step             models.Step
+	volumeManager    VolumeManager
+	containerManager ContainerManager
+	sequence         int
+}
+
+func NewLoadVar(
+	step models.Step,
+	volumeManager VolumeManager,
+	containerManager ContainerManager,
+) *LoadVar {
+	return &LoadVar{
+		step:             step,
+		volumeManager:    volumeManager,
+		containerManager: containerManager,
+	}
+}
+
+func (l *LoadVar) ID() string {
+	return fmt.Sprintf("load_var: %s (%d)", l.step.LoadVar.Name, l.sequence)
+}
+
+func (l *LoadVar) Tags() []string {
+	return l.step.Tags
+}
+
+func (l *LoadVar) Execute(stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	return l.ExecuteContext(context.Background(), stdout, stderr)
+}
+
+func (l *LoadVar) ExecuteContext(ctx context.Context, stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	outputs, ok := status.OutputsFromContext(ctx)
+	if !ok {
+		return status.Errored, fmt.Errorf("load var '%s' has no outputs to store into", l.step.LoadVar.Name)
+	}
+
+	parts := strings.SplitN(l.step.LoadVar.File, "/", 2)
+	if len(parts) != 2 {
+		return status.Errored, fmt.Errorf("load var file '%s' must be within an artifact", l.step.LoadVar.File)
+	}
+
+	runner := l.containerManager
+	runner.Context(ctx)
+	workingDir := fmt.Sprintf("/tmp/build/load-var-%s", generateBuildGUID())
+	runner.WorkingDir(workingDir)
+	runner.Volume(
+		l.volumeManager.Get(parts[0], false),
+		fmt.Sprintf("%s/%s", workingDir, parts[0]),
+	)
+	runner.Image("busybox", "latest")
+	runner.Command("cat", l.step.LoadVar.File)
+
+	contents := &bytes.Buffer{}
+	err := runner.Run(nil, contents, stderr)
+	if err != nil {
+		if _, ok := err.(*exec.ExitError); ok {
+			return status.Failed, err
+		}
+		return status.Errored, fmt.Errorf("load var execute errored: %s", err)
+	}
+
+	var value string
+	switch l.step.LoadVar.Format {
+	case "", "trim":
+		value = strings.TrimSpace(contents.String())
+	case "raw":
+		value = contents.String()
+	default:
+		return status.Errored, fmt.Errorf("load var format '%s' is not supported", l.step.LoadVar.Format)
+	}
+
+	outputs.Set(l, l.step.LoadVar.Name, value)
+	_, _ = fmt.Fprintf(stdout, "loaded var '%s'\n", l.step.LoadVar.Name)
+
+	return status.Success, nil
+}
+
+type localVars map[string]planner.Tasker
+
+func (v localVars) values(outputs status.Outputs) map[string]string {
+	values := map[string]string{}
+	for name, task := range v {
+		if value, ok := outputs.Get(task.ID(), name); ok {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+var _ planner.Tasker = &LoadVar{}
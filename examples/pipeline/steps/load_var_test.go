@@ -0,0 +1,122 @@
+package steps_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os/exec"
+
+	"github.com/jtarchie/dothings/examples/pipeline/steps"
+	"github.com/jtarchie/dothings/examples/pipeline/steps/stepsfakes"
+	"github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+const validLoadVar = `
+jobs:
+- name: test
+  plan:
+  - load_var: version
+    file: artifact/version
+`
+
+var _ = Describe("LoadVar", func() {
+	var (
+		loader           *steps.LoadVar
+		volumeManager    *stepsfakes.FakeVolumeManager
+		containerManager *stepsfakes.FakeContainerManager
+		outputs          status.Outputs
+		ctx              context.Context
+	)
+
+	BeforeEach(func() {
+		volumeManager = &stepsfakes.FakeVolumeManager{}
+		volumeManager.GetReturns("volume-guid")
+		containerManager = &stepsfakes.FakeContainerManager{}
+		containerManager.RunStub = func(stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
+			_, _ = stdout.Write([]byte("  1.2.3\n"))
+			return nil
+		}
+		loader = steps.NewLoadVar(
+			newTask(validLoadVar),
+			volumeManager,
+			containerManager,
+		)
+		outputs = status.NewOutputs()
+		ctx = status.WithOutputs(context.Background(), outputs)
+	})
+
+	It("returns a unique ID", func() {
+		Expect(loader.ID()).To(MatchRegexp(`^load_var: version \(\d+\)$`))
+	})
+
+	It("reads the file from the artifact", func() {
+		s, err := loader.ExecuteContext(ctx, ioutil.Discard, ioutil.Discard)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(s).To(Equal(status.Success))
+
+		Expect(volumeManager.GetArgsForCall(0)).To(Equal("artifact"))
+		from, to := containerManager.VolumeArgsForCall(0)
+		Expect(from).To(Equal("volume-guid"))
+		Expect(to).To(MatchRegexp(`/tmp/build/load-var-\w{6}/artifact`))
+
+		command, args := containerManager.CommandArgsForCall(0)
+		Expect(command).To(Equal("cat"))
+		Expect(args).To(Equal([]string{"artifact/version"}))
+	})
+
+	It("stores the trimmed value in the outputs", func() {
+		_, _ = loader.ExecuteContext(ctx, ioutil.Discard, ioutil.Discard)
+
+		value, ok := outputs.Get(loader.ID(), "version")
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal("1.2.3"))
+	})
+
+	It("stores the raw value when asked", func() {
+		loader = steps.NewLoadVar(
+			newTask(validLoadVar+"    format: raw\n"),
+			volumeManager,
+			containerManager,
+		)
+		_, _ = loader.ExecuteContext(ctx, ioutil.Discard, ioutil.Discard)
+
+		value, _ := outputs.Get(loader.ID(), "version")
+		Expect(value).To(Equal("  1.2.3\n"))
+	})
+
+	It("errors on an unsupported format", func() {
+		loader = steps.NewLoadVar(
+			newTask(validLoadVar+"    format: json\n"),
+			volumeManager,
+			containerManager,
+		)
+		s, err := loader.ExecuteContext(ctx, ioutil.Discard, ioutil.Discard)
+		Expect(err).To(MatchError("load var format 'json' is not supported"))
+		Expect(s).To(Equal(status.Errored))
+	})
+
+	It("errors without an outputs store", func() {
+		s, err := loader.Execute(ioutil.Discard, ioutil.Discard)
+		Expect(err).To(HaveOccurred())
+		Expect(s).To(Equal(status.Errored))
+	})
+
+	It("fails when the file cannot be read", func() {
+		containerManager.RunReturns(&exec.ExitError{})
+
+		s, err := loader.ExecuteContext(ctx, ioutil.Discard, ioutil.Discard)
+		Expect(err).To(BeAssignableToTypeOf(&exec.ExitError{}))
+		Expect(s).To(Equal(status.Failed))
+	})
+
+	It("errors on another error", func() {
+		containerManager.RunReturns(fmt.Errorf("some error"))
+
+		s, err := loader.ExecuteContext(ctx, ioutil.Discard, ioutil.Discard)
+		Expect(err).To(HaveOccurred())
+		Expect(s).To(Equal(status.Errored))
+	})
+})
@@ -1,8 +1,11 @@
 package docker
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"log"
 	"sort"
 )
 
@@ -16,6 +19,7 @@ type dockerManager struct {
 	commandExecutor CommandExecutor
 	privileged      bool
 	user            string
+	ctx             context.Context
 }
 
 func (d *dockerManager) Volume(local string, mountAs string) {
@@ -66,8 +70,10 @@ func (d *dockerManager) Run(
 		return ErrCommandRequired
 	}
 
+	name := fmt.Sprintf("dothings-%s", generateVolumeGUID())
 	args := []string{
 		"run", "-i", "--rm",
+		"--name", name,
 		"-w", d.workingDir,
 		"--entrypoint", "",
 	}
@@ -125,13 +131,47 @@ func (d *dockerManager) Run(
 	args = append(args, imageName)
 	args = append(args, d.command...)
 
-	return d.commandExecutor.Run(
+	stop := d.removeOnCancel(name)
+	err := d.commandExecutor.Run(
 		stdin,
 		stdout,
 		stderr,
 		"docker",
 		args...,
 	)
+	stop()
+
+	if d.ctx.Err() != nil {
+		return fmt.Errorf("container %s was cancelled: %s", name, d.ctx.Err())
+	}
+	return err
+}
+
+func (d *dockerManager) removeOnCancel(name string) func() {
+	done, removed := make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(removed)
+
+		select {
+		case <-d.ctx.Done():
+			err := d.commandExecutor.Run(
+				nil,
+				ioutil.Discard,
+				ioutil.Discard,
+				"docker",
+				"rm", "--force", name,
+			)
+			if err != nil {
+				log.Printf("could not remove container %s: %s", name, err)
+			}
+		case <-done:
+		}
+	}()
+
+	return func() {
+		close(done)
+		<-removed
+	}
 }
 
 func (d *dockerManager) Privileged(b bool) {
@@ -142,10 +182,15 @@ func (d *dockerManager) User(s string) {
 	d.user = s
 }
 
+func (d *dockerManager) Context(ctx context.Context) {
+	d.ctx = ctx
+}
+
 func NewDockerManager(runner CommandExecutor) *dockerManager {
 	return &dockerManager{
 		volumes:         map[string]string{},
 		env:             map[string]string{},
 		commandExecutor: runner,
+		ctx:             context.Background(),
 	}
 }
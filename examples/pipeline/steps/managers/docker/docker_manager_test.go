@@ -1,6 +1,9 @@
 package docker_test
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/jtarchie/dothings/examples/pipeline/steps/managers/docker"
 	"github.com/jtarchie/dothings/examples/pipeline/steps/managers/docker/dockerfakes"
 	. "github.com/onsi/ginkgo"
@@ -109,6 +112,40 @@ var _ = Describe("DockerManager", func() {
 		})
 	})
 
+	When("the context is cancelled", func() {
+		It("removes the running container", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			removed := make(chan struct{})
+
+			executor := &dockerfakes.FakeCommandExecutor{}
+			executor.RunStub = func(_ io.Reader, _ io.Writer, _ io.Writer, _ string, args ...string) error {
+				if args[0] == "rm" {
+					close(removed)
+					return nil
+				}
+				cancel()
+				<-removed
+				return fmt.Errorf("exit status 137")
+			}
+
+			runner := docker.NewDockerManager(executor)
+			runner.WorkingDir("/tmp")
+			runner.Image("ubuntu", "")
+			runner.Command("sleep", "100")
+			runner.Context(ctx)
+
+			err := runner.Run(nil, GinkgoWriter, GinkgoWriter)
+			Expect(err).To(MatchError(ContainSubstring("was cancelled")))
+
+			Expect(executor.RunCallCount()).To(Equal(2))
+			_, _, _, _, args := executor.RunArgsForCall(0)
+			Expect(args[3:5]).To(Equal([]string{"--name", args[4]}))
+			_, _, _, command, removal := executor.RunArgsForCall(1)
+			Expect(command).To(Equal("docker"))
+			Expect(removal).To(Equal([]string{"rm", "--force", args[4]}))
+		})
+	})
+
 	When("user is set", func() {
 		It("starts the container with that user", func() {
 			executor := &dockerfakes.FakeCommandExecutor{}
@@ -16,6 +16,10 @@ func (f *factory) VolumeManager() steps.VolumeManager {
 	return f.resourceVolumeManager
 }
 
+func (f *factory) Destroy() error {
+	return f.resourceVolumeManager.Destroy()
+}
+
 func (f *factory) NewContainerManager() steps.ContainerManager {
 	return nil
 }
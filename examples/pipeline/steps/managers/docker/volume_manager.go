@@ -3,8 +3,12 @@ package docker
 import (
 	"crypto/rand"
 	"encoding/base32"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"sort"
+	"strings"
 	"sync"
 )
 
@@ -48,6 +52,74 @@ func (vm *resourceVolumeManager) All() map[string]string {
 	return vm.volumes
 }
 
+func (vm *resourceVolumeManager) Destroy() error {
+	vm.Lock()
+	defer vm.Unlock()
+
+	names := []string{}
+	for name := range vm.volumes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	failures := []string{}
+	for _, name := range names {
+		err := vm.commandExecutor.Run(
+			nil,
+			os.Stderr,
+			os.Stderr,
+			"docker",
+			"volume", "rm",
+			"--force",
+			vm.volumes[name],
+		)
+		if err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %s", name, err))
+			continue
+		}
+		delete(vm.volumes, name)
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("could not remove volumes: %s", strings.Join(failures, ", "))
+	}
+	return nil
+}
+
+func (vm *resourceVolumeManager) Export(name string, w io.Writer) error {
+	err := vm.commandExecutor.Run(
+		nil,
+		w,
+		os.Stderr,
+		"docker",
+		"run", "--rm",
+		"--volume", fmt.Sprintf("%s:/volume", vm.Get(name, false)),
+		"busybox",
+		"tar", "-c", "-C", "/volume", ".",
+	)
+	if err != nil {
+		return fmt.Errorf("could not export volume %s: %s", name, err)
+	}
+	return nil
+}
+
+func (vm *resourceVolumeManager) Import(name string, r io.Reader) error {
+	err := vm.commandExecutor.Run(
+		r,
+		os.Stderr,
+		os.Stderr,
+		"docker",
+		"run", "--rm", "--interactive",
+		"--volume", fmt.Sprintf("%s:/volume", vm.Get(name, false)),
+		"busybox",
+		"sh", "-c", "find /volume -mindepth 1 -delete && tar -x -C /volume",
+	)
+	if err != nil {
+		return fmt.Errorf("could not import volume %s: %s", name, err)
+	}
+	return nil
+}
+
 func NewResourceVolumeManager(
 	commandExecutor CommandExecutor,
 ) *resourceVolumeManager {
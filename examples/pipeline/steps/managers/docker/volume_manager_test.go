@@ -0,0 +1,79 @@
+package docker_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/jtarchie/dothings/examples/pipeline/steps/managers/docker"
+	"github.com/jtarchie/dothings/examples/pipeline/steps/managers/docker/dockerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("VolumeManager", func() {
+	It("removes every volume it created", func() {
+		executor := &dockerfakes.FakeCommandExecutor{}
+		volumes := docker.NewResourceVolumeManager(executor)
+
+		created := volumes.Get("resource", false)
+		Expect(volumes.Destroy()).To(Succeed())
+		Expect(volumes.All()).To(BeEmpty())
+
+		Expect(executor.RunCallCount()).To(Equal(2))
+		_, _, _, command, args := executor.RunArgsForCall(1)
+		Expect(command).To(Equal("docker"))
+		Expect(args).To(Equal([]string{"volume", "rm", "--force", created}))
+	})
+
+	It("removes the other volumes when one cannot be removed", func() {
+		executor := &dockerfakes.FakeCommandExecutor{}
+		volumes := docker.NewResourceVolumeManager(executor)
+		volumes.Get("a", false)
+		busy := volumes.Get("b", false)
+		volumes.Get("c", false)
+
+		executor.RunStub = func(_ io.Reader, _ io.Writer, _ io.Writer, _ string, args ...string) error {
+			if args[len(args)-1] == busy {
+				return fmt.Errorf("volume is in use")
+			}
+			return nil
+		}
+		Expect(volumes.Destroy()).To(MatchError("could not remove volumes: b: volume is in use"))
+		Expect(volumes.All()).To(Equal(map[string]string{"b": busy}))
+	})
+
+	It("exports and imports the contents of a volume as a tar", func() {
+		executor := &dockerfakes.FakeCommandExecutor{}
+		volumes := docker.NewResourceVolumeManager(executor)
+		created := volumes.Get("binary", false)
+
+		archive := &bytes.Buffer{}
+		Expect(volumes.Export("binary", archive)).To(Succeed())
+		_, stdout, _, command, args := executor.RunArgsForCall(1)
+		Expect(stdout).To(Equal(archive))
+		Expect(command).To(Equal("docker"))
+		Expect(args).To(Equal([]string{
+			"run", "--rm",
+			"--volume", created + ":/volume",
+			"busybox",
+			"tar", "-c", "-C", "/volume", ".",
+		}))
+
+		Expect(volumes.Import("binary", archive)).To(Succeed())
+		stdin, _, _, _, args := executor.RunArgsForCall(2)
+		Expect(stdin).To(Equal(archive))
+		Expect(args).To(ContainElement(created + ":/volume"))
+		Expect(args[len(args)-1]).To(ContainSubstring("tar -x -C /volume"))
+	})
+
+	It("errors when the volume cannot be exported", func() {
+		executor := &dockerfakes.FakeCommandExecutor{}
+		volumes := docker.NewResourceVolumeManager(executor)
+		volumes.Get("binary", false)
+
+		executor.RunReturns(fmt.Errorf("no such image"))
+
+		Expect(volumes.Export("binary", &bytes.Buffer{})).To(MatchError("could not export volume binary: no such image"))
+	})
+})
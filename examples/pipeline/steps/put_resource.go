@@ -7,7 +7,6 @@ import (
 	"io"
 	"os/exec"
 	"sort"
-	"time"
 
 	"github.com/jtarchie/dothings/examples/pipeline/models"
 	"github.com/jtarchie/dothings/planner"
@@ -20,7 +19,7 @@ type PutResource struct {
 	volumeManager    VolumeManager
 	containerManager ContainerManager
 	params           map[string]interface{}
-	timestamp        int64
+	sequence         int
 }
 
 func NewPutResource(
@@ -36,12 +35,11 @@ func NewPutResource(
 		volumeManager:    volumeManager,
 		containerManager: containerManager,
 		params:           params,
-		timestamp:        time.Now().UnixNano(),
 	}
 }
 
 func (p *PutResource) ID() string {
-	return fmt.Sprintf("put resource: %s (%d)", p.resource.Name, p.timestamp)
+	return fmt.Sprintf("put resource: %s (%d)", p.resource.Name, p.sequence)
 }
 
 func (p *PutResource) Execute(stdout io.Writer, stderr io.Writer) (status.Type, error) {
@@ -89,7 +87,7 @@ func (p *PutResource) Execute(stdout io.Writer, stderr io.Writer) (status.Type,
 
 	if err != nil {
 		if _, ok := err.(*exec.ExitError); ok {
-			return status.Failed, nil
+			return status.Failed, err
 		}
 		return status.Errored, fmt.Errorf("check resource execute errored: %s", err)
 	}
@@ -149,7 +149,7 @@ var _ = Describe("PutResource", func() {
 				}
 
 				s, err := put.Execute(ioutil.Discard, ioutil.Discard)
-				Expect(err).ToNot(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(&exec.ExitError{}))
 				Expect(s).To(Equal(status.Failed))
 			})
 
@@ -2,6 +2,7 @@
 package stepsfakes
 
 import (
+	"context"
 	"io"
 	"sync"
 )
@@ -13,6 +14,11 @@ type FakeContainerManager struct {
 		arg1 string
 		arg2 []string
 	}
+	ContextStub        func(context.Context)
+	contextMutex       sync.RWMutex
+	contextArgsForCall []struct {
+		arg1 context.Context
+	}
 	EnvVarStub        func(string, string)
 	envVarMutex       sync.RWMutex
 	envVarArgsForCall []struct {
@@ -100,6 +106,37 @@ func (fake *FakeContainerManager) CommandArgsForCall(i int) (string, []string) {
 	return argsForCall.arg1, argsForCall.arg2
 }
 
+func (fake *FakeContainerManager) Context(arg1 context.Context) {
+	fake.contextMutex.Lock()
+	fake.contextArgsForCall = append(fake.contextArgsForCall, struct {
+		arg1 context.Context
+	}{arg1})
+	fake.recordInvocation("Context", []interface{}{arg1})
+	fake.contextMutex.Unlock()
+	if fake.ContextStub != nil {
+		fake.ContextStub(arg1)
+	}
+}
+
+func (fake *FakeContainerManager) ContextCallCount() int {
+	fake.contextMutex.RLock()
+	defer fake.contextMutex.RUnlock()
+	return len(fake.contextArgsForCall)
+}
+
+func (fake *FakeContainerManager) ContextCalls(stub func(context.Context)) {
+	fake.contextMutex.Lock()
+	defer fake.contextMutex.Unlock()
+	fake.ContextStub = stub
+}
+
+func (fake *FakeContainerManager) ContextArgsForCall(i int) context.Context {
+	fake.contextMutex.RLock()
+	defer fake.contextMutex.RUnlock()
+	argsForCall := fake.contextArgsForCall[i]
+	return argsForCall.arg1
+}
+
 func (fake *FakeContainerManager) EnvVar(arg1 string, arg2 string) {
 	fake.envVarMutex.Lock()
 	fake.envVarArgsForCall = append(fake.envVarArgsForCall, struct {
@@ -387,6 +424,8 @@ func (fake *FakeContainerManager) Invocations() map[string][][]interface{} {
 	defer fake.invocationsMutex.RUnlock()
 	fake.commandMutex.RLock()
 	defer fake.commandMutex.RUnlock()
+	fake.contextMutex.RLock()
+	defer fake.contextMutex.RUnlock()
 	fake.envVarMutex.RLock()
 	defer fake.envVarMutex.RUnlock()
 	fake.imageMutex.RLock()
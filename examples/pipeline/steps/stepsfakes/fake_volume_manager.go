@@ -2,6 +2,7 @@
 package stepsfakes
 
 import (
+	"io"
 	"sync"
 )
 
@@ -16,6 +17,18 @@ type FakeVolumeManager struct {
 	allReturnsOnCall map[int]struct {
 		result1 map[string]string
 	}
+	ExportStub        func(string, io.Writer) error
+	exportMutex       sync.RWMutex
+	exportArgsForCall []struct {
+		arg1 string
+		arg2 io.Writer
+	}
+	exportReturns struct {
+		result1 error
+	}
+	exportReturnsOnCall map[int]struct {
+		result1 error
+	}
 	GetStub        func(string) string
 	getMutex       sync.RWMutex
 	getArgsForCall []struct {
@@ -27,6 +40,18 @@ type FakeVolumeManager struct {
 	getReturnsOnCall map[int]struct {
 		result1 string
 	}
+	ImportStub        func(string, io.Reader) error
+	importMutex       sync.RWMutex
+	importArgsForCall []struct {
+		arg1 string
+		arg2 io.Reader
+	}
+	importReturns struct {
+		result1 error
+	}
+	importReturnsOnCall map[int]struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -83,6 +108,67 @@ func (fake *FakeVolumeManager) AllReturnsOnCall(i int, result1 map[string]string
 	}{result1}
 }
 
+func (fake *FakeVolumeManager) Export(arg1 string, arg2 io.Writer) error {
+	fake.exportMutex.Lock()
+	ret, specificReturn := fake.exportReturnsOnCall[len(fake.exportArgsForCall)]
+	fake.exportArgsForCall = append(fake.exportArgsForCall, struct {
+		arg1 string
+		arg2 io.Writer
+	}{arg1, arg2})
+	fake.recordInvocation("Export", []interface{}{arg1, arg2})
+	fake.exportMutex.Unlock()
+	if fake.ExportStub != nil {
+		return fake.ExportStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.exportReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeVolumeManager) ExportCallCount() int {
+	fake.exportMutex.RLock()
+	defer fake.exportMutex.RUnlock()
+	return len(fake.exportArgsForCall)
+}
+
+func (fake *FakeVolumeManager) ExportCalls(stub func(string, io.Writer) error) {
+	fake.exportMutex.Lock()
+	defer fake.exportMutex.Unlock()
+	fake.ExportStub = stub
+}
+
+func (fake *FakeVolumeManager) ExportArgsForCall(i int) (string, io.Writer) {
+	fake.exportMutex.RLock()
+	defer fake.exportMutex.RUnlock()
+	argsForCall := fake.exportArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeVolumeManager) ExportReturns(result1 error) {
+	fake.exportMutex.Lock()
+	defer fake.exportMutex.Unlock()
+	fake.ExportStub = nil
+	fake.exportReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVolumeManager) ExportReturnsOnCall(i int, result1 error) {
+	fake.exportMutex.Lock()
+	defer fake.exportMutex.Unlock()
+	fake.ExportStub = nil
+	if fake.exportReturnsOnCall == nil {
+		fake.exportReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.exportReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeVolumeManager) Get(arg1 string, b bool) string {
 	fake.getMutex.Lock()
 	ret, specificReturn := fake.getReturnsOnCall[len(fake.getArgsForCall)]
@@ -143,13 +229,78 @@ func (fake *FakeVolumeManager) GetReturnsOnCall(i int, result1 string) {
 	}{result1}
 }
 
+func (fake *FakeVolumeManager) Import(arg1 string, arg2 io.Reader) error {
+	fake.importMutex.Lock()
+	ret, specificReturn := fake.importReturnsOnCall[len(fake.importArgsForCall)]
+	fake.importArgsForCall = append(fake.importArgsForCall, struct {
+		arg1 string
+		arg2 io.Reader
+	}{arg1, arg2})
+	fake.recordInvocation("Import", []interface{}{arg1, arg2})
+	fake.importMutex.Unlock()
+	if fake.ImportStub != nil {
+		return fake.ImportStub(arg1, arg2)
+	}
+	if specificReturn {
+		return ret.result1
+	}
+	fakeReturns := fake.importReturns
+	return fakeReturns.result1
+}
+
+func (fake *FakeVolumeManager) ImportCallCount() int {
+	fake.importMutex.RLock()
+	defer fake.importMutex.RUnlock()
+	return len(fake.importArgsForCall)
+}
+
+func (fake *FakeVolumeManager) ImportCalls(stub func(string, io.Reader) error) {
+	fake.importMutex.Lock()
+	defer fake.importMutex.Unlock()
+	fake.ImportStub = stub
+}
+
+func (fake *FakeVolumeManager) ImportArgsForCall(i int) (string, io.Reader) {
+	fake.importMutex.RLock()
+	defer fake.importMutex.RUnlock()
+	argsForCall := fake.importArgsForCall[i]
+	return argsForCall.arg1, argsForCall.arg2
+}
+
+func (fake *FakeVolumeManager) ImportReturns(result1 error) {
+	fake.importMutex.Lock()
+	defer fake.importMutex.Unlock()
+	fake.ImportStub = nil
+	fake.importReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeVolumeManager) ImportReturnsOnCall(i int, result1 error) {
+	fake.importMutex.Lock()
+	defer fake.importMutex.Unlock()
+	fake.ImportStub = nil
+	if fake.importReturnsOnCall == nil {
+		fake.importReturnsOnCall = make(map[int]struct {
+			result1 error
+		})
+	}
+	fake.importReturnsOnCall[i] = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeVolumeManager) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
 	fake.allMutex.RLock()
 	defer fake.allMutex.RUnlock()
+	fake.exportMutex.RLock()
+	defer fake.exportMutex.RUnlock()
 	fake.getMutex.RLock()
 	defer fake.getMutex.RUnlock()
+	fake.importMutex.RLock()
+	defer fake.importMutex.RUnlock()
 	copiedInvocations := map[string][][]interface{}{}
 	for key, value := range fake.invocations {
 		copiedInvocations[key] = value
@@ -1,15 +1,20 @@
 package steps
 
 import (
+	"archive/tar"
+	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/base32"
+	"encoding/hex"
 	"fmt"
+	"hash"
 	"io"
 	"os/exec"
 	"sort"
-	"time"
 
 	"github.com/jtarchie/dothings/examples/pipeline/models"
+	"gopkg.in/yaml.v2"
 
 	"github.com/jtarchie/dothings/planner"
 	"github.com/jtarchie/dothings/status"
@@ -19,7 +24,8 @@ type Task struct {
 	step             models.Step
 	volumeManager    VolumeManager
 	containerManager ContainerManager
-	timestamp        int64
+	sequence         int
+	vars             localVars
 }
 
 func NewTask(
@@ -31,17 +37,80 @@ func NewTask(
 		step:             step,
 		volumeManager:    volumeManager,
 		containerManager: containerManager,
-		timestamp:        time.Now().UnixNano(),
 	}
 }
 
 func (t *Task) ID() string {
-	return fmt.Sprintf("task: %s (%d)", t.step.Task.Name, t.timestamp)
+	return fmt.Sprintf("task: %s (%d)", t.step.Task.Name, t.sequence)
+}
+
+func (t *Task) Tags() []string {
+	return t.step.Tags
+}
+
+func (t *Task) CacheKey() (string, bool, error) {
+	if len(t.vars) > 0 || t.step.Task.Image == "" {
+		return "", false, nil
+	}
+
+	config, err := yaml.Marshal(t.step.Task)
+	if err != nil {
+		return "", false, fmt.Errorf("could not encode task config: %s", err)
+	}
+
+	digest := sha256.New()
+	_, _ = digest.Write(config)
+
+	volumes := []string{t.step.Task.Image}
+	for _, input := range t.step.Task.Config.Inputs {
+		volumes = append(volumes, input.Name)
+	}
+	for _, name := range volumes {
+		_, _ = fmt.Fprintf(digest, "volume %s\n", name)
+		err = t.hashVolume(digest, name)
+		if err != nil {
+			return "", false, fmt.Errorf("could not hash volume %s: %s", name, err)
+		}
+	}
+
+	return hex.EncodeToString(digest.Sum(nil)), true, nil
+}
+
+func (t *Task) Artifacts() []string {
+	names := []string{}
+	for _, output := range t.step.Task.Config.Outputs {
+		names = append(names, output.Name)
+	}
+	return names
+}
+
+func (t *Task) SaveArtifact(name string, w io.Writer) error {
+	return t.volumeManager.Export(name, w)
+}
+
+func (t *Task) RestoreArtifact(name string, r io.Reader) error {
+	return t.volumeManager.Import(name, r)
+}
+
+func (t *Task) ExecuteContext(ctx context.Context, stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	outputs, ok := status.OutputsFromContext(ctx)
+	if !ok || len(t.vars) == 0 {
+		return t.run(ctx, stdout, stderr)
+	}
+
+	interpolated := *t
+	interpolated.step = t.step.Interpolate(t.vars.values(outputs))
+	return interpolated.run(ctx, stdout, stderr)
 }
 
 func (t *Task) Execute(stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	return t.run(context.Background(), stdout, stderr)
+}
+
+func (t *Task) run(ctx context.Context, stdout io.Writer, stderr io.Writer) (status.Type, error) {
 	workingPath := fmt.Sprintf("/tmp/build/%s", generateBuildGUID())
 	runner := t.containerManager
+	runner.Context(ctx)
 
 	t.setupWorkingDirectory(runner, workingPath)
 	t.setupInputs(workingPath)
@@ -61,8 +130,11 @@ func (t *Task) Execute(stdout io.Writer, stderr io.Writer) (status.Type, error)
 	)
 
 	if err != nil {
+		if ctx.Err() != nil {
+			return status.Failed, err
+		}
 		if _, ok := err.(*exec.ExitError); ok {
-			return status.Failed, nil
+			return status.Failed, err
 		}
 		return status.Errored, fmt.Errorf("check resource execute errored: %s", err)
 	}
@@ -143,6 +215,31 @@ func (t *Task) setupCommand() {
 	t.containerManager.Command(t.step.Task.Config.Run.Path, t.step.Task.Config.Run.Args...)
 }
 
+func (t *Task) hashVolume(digest hash.Hash, name string) error {
+	reader, writer := io.Pipe()
+	go func() {
+		writer.CloseWithError(t.volumeManager.Export(name, writer))
+	}()
+	defer reader.Close()
+
+	archive := tar.NewReader(reader)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		_, _ = fmt.Fprintf(digest, "%s %s %s\n", header.Name, header.FileInfo().Mode(), header.Linkname)
+		_, err = io.Copy(digest, archive)
+		if err != nil {
+			return err
+		}
+	}
+}
+
 func generateBuildGUID() string {
 	buffer := make([]byte, 10)
 	_, _ = rand.Reader.Read(buffer)
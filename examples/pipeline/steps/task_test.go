@@ -1,6 +1,9 @@
 package steps_test
 
 import (
+	"archive/tar"
+	"bytes"
+	"context"
 	"fmt"
 	"github.com/onsi/gomega/gbytes"
 	"io"
@@ -54,6 +57,18 @@ var _ = Describe("Task", func() {
 		Expect(check.ID()).To(ContainSubstring("task: testing"))
 	})
 
+	It("routes by the tags of the step", func() {
+		step := newTask(validTask)
+		step.Tags = []string{"linux", "large"}
+
+		task := steps.NewTask(
+			step,
+			&stepsfakes.FakeVolumeManager{},
+			&stepsfakes.FakeContainerManager{},
+		)
+		Expect(task.Tags()).To(Equal([]string{"linux", "large"}))
+	})
+
 	When("executing valid task", func() {
 		var (
 			task             *steps.Task
@@ -152,6 +167,19 @@ var _ = Describe("Task", func() {
 			Expect(args).To(Equal([]string{"hello world"}))
 		})
 
+		It("stops the container when cancelled", func() {
+			ctx, cancel := context.WithCancel(context.Background())
+			containerManager.RunStub = func(io.Reader, io.Writer, io.Writer) error {
+				cancel()
+				return fmt.Errorf("container was cancelled")
+			}
+
+			s, err := task.ExecuteContext(ctx, ioutil.Discard, ioutil.Discard)
+			Expect(err).To(HaveOccurred())
+			Expect(s).To(Equal(status.Failed))
+			Expect(containerManager.ContextArgsForCall(0)).To(Equal(ctx))
+		})
+
 		When("the container fails", func() {
 			It("fails on an exit code failure", func() {
 				containerManager.RunStub = func(stdin io.Reader, stdout io.Writer, stderr io.Writer) error {
@@ -159,7 +187,7 @@ var _ = Describe("Task", func() {
 				}
 
 				s, err := task.Execute(ioutil.Discard, ioutil.Discard)
-				Expect(err).ToNot(HaveOccurred())
+				Expect(err).To(BeAssignableToTypeOf(&exec.ExitError{}))
 				Expect(s).To(Equal(status.Failed))
 			})
 
@@ -218,6 +246,135 @@ jobs:
 		})
 	})
 
+	When("computing a cache key", func() {
+		const cacheableTask = `
+jobs:
+- name: test
+  plan:
+  - task: testing
+    image: resource
+    config:
+      platform: linux
+      inputs:
+      - name: source
+      run:
+        path: make
+`
+
+		var (
+			task          *steps.Task
+			volumeManager *stepsfakes.FakeVolumeManager
+			volumes       map[string]map[string]string
+		)
+
+		BeforeEach(func() {
+			volumes = map[string]map[string]string{
+				"resource": {"rootfs/bin/make": "binary"},
+				"source":   {"main.c": "int main() {}"},
+			}
+
+			volumeManager = &stepsfakes.FakeVolumeManager{}
+			volumeManager.ExportStub = func(name string, w io.Writer) error {
+				archive := tar.NewWriter(w)
+				for path, contents := range volumes[name] {
+					err := archive.WriteHeader(&tar.Header{Name: path, Mode: 0644, Size: int64(len(contents))})
+					if err != nil {
+						return err
+					}
+					_, err = io.WriteString(archive, contents)
+					if err != nil {
+						return err
+					}
+				}
+				return archive.Close()
+			}
+			task = steps.NewTask(
+				newTask(cacheableTask),
+				volumeManager,
+				&stepsfakes.FakeContainerManager{},
+			)
+		})
+
+		It("is stable for the same config and inputs", func() {
+			first, ok, err := task.CacheKey()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			second, _, err := steps.NewTask(
+				newTask(cacheableTask),
+				volumeManager,
+				&stepsfakes.FakeContainerManager{},
+			).CacheKey()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).To(Equal(first))
+		})
+
+		It("changes when the contents of an input change", func() {
+			first, _, err := task.CacheKey()
+			Expect(err).NotTo(HaveOccurred())
+
+			volumes["source"]["main.c"] = "int main() { return 1; }"
+
+			second, _, err := task.CacheKey()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).NotTo(Equal(first))
+		})
+
+		It("changes when the params change", func() {
+			first, _, err := task.CacheKey()
+			Expect(err).NotTo(HaveOccurred())
+
+			step := newTask(cacheableTask)
+			step.Task.Config.Params = map[string]string{"DEBUG": "1"}
+			second, _, err := steps.NewTask(step, volumeManager, &stepsfakes.FakeContainerManager{}).CacheKey()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(second).NotTo(Equal(first))
+		})
+
+		It("errors when an input cannot be read", func() {
+			volumeManager.ExportStub = nil
+			volumeManager.ExportReturns(fmt.Errorf("no such volume"))
+
+			_, ok, err := task.CacheKey()
+			Expect(err).To(MatchError("could not hash volume resource: no such volume"))
+			Expect(ok).To(BeFalse())
+		})
+
+		It("is not cacheable when its image is pulled by tag", func() {
+			_, ok, err := steps.NewTask(
+				newTask(validTask),
+				volumeManager,
+				&stepsfakes.FakeContainerManager{},
+			).CacheKey()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		It("saves and restores its outputs as artifacts", func() {
+			step := newTask(validTask)
+			step.Task.Image = "resource"
+			task := steps.NewTask(
+				step,
+				volumeManager,
+				&stepsfakes.FakeContainerManager{},
+			)
+			_, ok, err := task.CacheKey()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+			Expect(task.Artifacts()).To(Equal([]string{"output-2"}))
+
+			archive := &bytes.Buffer{}
+			Expect(task.SaveArtifact("output-2", archive)).To(Succeed())
+			name, _ := volumeManager.ExportArgsForCall(volumeManager.ExportCallCount() - 1)
+			Expect(name).To(Equal("output-2"))
+
+			Expect(task.RestoreArtifact("output-2", archive)).To(Succeed())
+			name, reader := volumeManager.ImportArgsForCall(0)
+			Expect(name).To(Equal("output-2"))
+			Expect(reader).To(Equal(archive))
+		})
+	})
+
 	XWhen("a docker image resource is provided", func() {
 		It("uses the repository for the container", func() {
 
@@ -0,0 +1,81 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/jtarchie/dothings/executor/writers"
+	"github.com/jtarchie/dothings/journal"
+)
+
+func main() {
+	journalFile := flag.String("journal", "", "journal of the build to replay")
+	port := flag.Int("port", 8080, "port of the http server")
+	flag.Parse()
+
+	file, err := os.Open(*journalFile)
+	if err != nil {
+		log.Fatalf("could not open journal: %s", err)
+	}
+	replay, err := journal.NewReplay(file)
+	_ = file.Close()
+	if err != nil {
+		log.Fatalf("could not load journal: %s", err)
+	}
+	if replay.Len() == 0 {
+		log.Fatalf("journal has no events to replay")
+	}
+
+	events := replay.Events()
+	started := events[0].At
+	log.Printf("replaying %d events over %s", replay.Len(), events[len(events)-1].At.Sub(started))
+	for _, event := range events {
+		if event.Type == journal.Hook {
+			log.Printf("%s hook entered at +%s by %s", event.Hook, event.At.Sub(started), event.TaskID)
+		}
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		var build *journal.Build
+		var err error
+
+		switch {
+		case r.FormValue("step") != "":
+			step, parseErr := strconv.Atoi(r.FormValue("step"))
+			if parseErr != nil {
+				http.Error(w, fmt.Sprintf("could not parse step: %s", parseErr), http.StatusBadRequest)
+				return
+			}
+			build, err = replay.Step(step)
+		case r.FormValue("at") != "":
+			offset, parseErr := time.ParseDuration(r.FormValue("at"))
+			if parseErr != nil {
+				http.Error(w, fmt.Sprintf("could not parse at: %s", parseErr), http.StatusBadRequest)
+				return
+			}
+			build, err = replay.Until(started.Add(offset))
+		default:
+			build, err = replay.Step(replay.Len())
+		}
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		writers.NewWebHandlerWithClock(
+			build.Plan,
+			build.Writer,
+			build.Stater,
+			nil,
+			func() time.Time { return build.At },
+		).ServeHTTP(w, r)
+	})
+
+	log.Printf("listening on http://localhost:%d (step through with ?step=N or ?at=DURATION)", *port)
+	log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", *port), nil))
+}
@@ -1,15 +1,20 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	"time"
 
 	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/executor/remote"
 
 	"github.com/jtarchie/dothings/executor/writers"
 	"github.com/jtarchie/dothings/planner"
@@ -25,25 +30,39 @@ func (d *dotter) ID() string {
 	return fmt.Sprintf("dotter %s", d.duration)
 }
 
-func (d *dotter) Execute(stdout io.Writer, _ io.Writer) (status.Type, error) {
+func (d *dotter) Execute(stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	return d.ExecuteContext(context.Background(), stdout, stderr)
+}
+
+func (d *dotter) ExecuteContext(ctx context.Context, stdout io.Writer, _ io.Writer) (status.Type, error) {
+	ticker := time.NewTicker(d.polling)
+	defer ticker.Stop()
+
 	duration := d.duration
-	for range time.Tick(d.polling) {
+	for duration > 0 {
+		select {
+		case <-ctx.Done():
+			return status.Failed, ctx.Err()
+		case <-ticker.C:
+		}
 		_, _ = fmt.Fprintf(stdout, "%s remaining\n", duration)
 		duration = duration - d.polling
-		if duration <= 0 {
-			break
-		}
 	}
 	return status.Success, nil
 }
 
-var _ executor.Tasker = &dotter{}
+var _ executor.ContextTasker = &dotter{}
 
 func main() {
 	pollingIntervalStr := flag.String("polling-interval", "5s", "the duration for the")
 	durationStr := flag.String("duration", "10s", "the duration for the ")
 	numTasks := flag.Int("num-tasks", 10, "the number of tasks to run")
 	port := flag.Int("port", 8080, "port of the http server")
+	dispatch := flag.Bool("dispatch", false, "run tasks on workers registered at /workers instead of locally")
+	workFor := flag.String("worker", "", "run as a worker for the coordinator at this url")
+	tags := flag.String("tags", "", "comma separated tags offered when running as a worker")
+	capacity := flag.Int("capacity", 1, "number of tasks to run at once when running as a worker")
+	grace := flag.Duration("grace", 30*time.Second, "time for running tasks to stop after an interrupt before exiting")
 
 	flag.Parse()
 
@@ -73,6 +92,32 @@ func main() {
 		return nil
 	})
 
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, syscall.SIGINT, syscall.SIGTERM)
+
+	if *workFor != "" {
+		offered := []string{}
+		if *tags != "" {
+			offered = strings.Split(*tags, ",")
+		}
+		hostname, _ := os.Hostname()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		go func() {
+			received := <-signals
+			log.Printf("received %s, stopping the worker", received)
+			cancel()
+		}()
+
+		log.Printf("working for %s", *workFor)
+		worker := remote.NewWorker(*workFor, hostname, offered, *capacity, remote.NewPlanResolver(plan))
+		err = worker.Run(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	log.Println("starting execution")
 	inMemory := writers.NewInMemory()
 	statuses := status.NewStatuses()
@@ -80,24 +125,68 @@ func main() {
 
 	http.Handle("/", handler)
 
-	go executor.NewExecutorWithStater(
+	var dispatcher executor.Dispatcher
+	if *dispatch {
+		coordinator := remote.NewCoordinator()
+		http.Handle("/workers/", http.StripPrefix("/workers", coordinator))
+		dispatcher = coordinator
+	}
+
+	events, stop := statuses.Watch()
+	defer stop()
+
+	execution := executor.NewExecutorWithStater(
 		plan,
 		inMemory,
 		statuses,
-	).Wait()
+		executor.WithDispatcher(dispatcher),
+	)
+	finished := make(chan status.Type, 1)
+	go func() {
+		finished <- execution.Wait()
+	}()
 
+	server := &http.Server{Addr: fmt.Sprintf(":%d", *port)}
 	log.Printf("listening on http://localhost:%d", *port)
 	log.Printf("current status: %s", plan.State(statuses))
 	go func() {
-		log.Fatal(http.ListenAndServe(fmt.Sprintf(":%d", *port), nil))
+		err := server.ListenAndServe()
+		if err != http.ErrServerClosed {
+			log.Fatal(err)
+		}
+	}()
+
+	go func() {
+		for event := range events {
+			log.Printf("task %s (attempt %d): %s -> %s", event.TaskID, event.Attempt, event.From, event.To)
+		}
 	}()
 
-	for {
-		log.Printf("current status: %s", plan.State(statuses))
-		if plan.State(statuses) == status.Success {
-			log.Println("successfully finished, exiting")
-			os.Exit(0)
+	var finalState status.Type
+	select {
+	case finalState = <-finished:
+	case received := <-signals:
+		log.Printf("received %s, aborting the build", received)
+		execution.Abort()
+
+		select {
+		case finalState = <-finished:
+		case <-time.After(*grace):
+			log.Printf("build did not stop within %s", *grace)
+			finalState = status.Errored
+		case <-signals:
+			log.Println("received a second signal, exiting immediately")
+			finalState = status.Errored
 		}
-		time.Sleep(pollingInterval)
 	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err = server.Shutdown(ctx)
+	if err != nil {
+		log.Printf("could not stop http server: %s", err)
+	}
+
+	log.Printf("finished with %s, exiting", finalState)
+	os.Exit(finalState.ExitCode())
 }
@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"time"
+
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+type GateTasker interface {
+	Tasker
+	ApprovalTimeout() time.Duration
+}
+
+func gated(task Tasker) (GateTasker, bool) {
+	for _, unit := range planner.Unwrap(task) {
+		if gate, ok := unit.(GateTasker); ok {
+			return gate, true
+		}
+	}
+	return nil, false
+}
+
+func (e *Executor) Approve(id string) error {
+	return e.decide(id, true)
+}
+
+func (e *Executor) Reject(id string) error {
+	return e.decide(id, false)
+}
+
+func (e *Executor) decide(id string, approved bool) error {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	decision, ok := e.gates[id]
+	if !ok {
+		return fmt.Errorf("task '%s' is not waiting for approval", id)
+	}
+	delete(e.gates, id)
+	decision <- approved
+
+	return nil
+}
+
+func (e *Executor) await(ctx context.Context, task Tasker, gate GateTasker, stdout io.Writer) (status.Type, error) {
+	decision := make(chan bool, 1)
+
+	e.lock.Lock()
+	e.gates[task.ID()] = decision
+	e.lock.Unlock()
+
+	defer func() {
+		e.lock.Lock()
+		defer e.lock.Unlock()
+		delete(e.gates, task.ID())
+	}()
+
+	err := e.stater.Add(task, status.Pending)
+	if err != nil {
+		return status.Errored, fmt.Errorf("could not mark task %s as Pending: %s", task.ID(), err)
+	}
+	_, _ = fmt.Fprintln(stdout, "waiting for approval")
+
+	var timeout <-chan time.Time
+	if duration := gate.ApprovalTimeout(); duration > 0 {
+		timer := time.NewTimer(duration)
+		defer timer.Stop()
+		timeout = timer.C
+	}
+
+	select {
+	case approved := <-decision:
+		if approved {
+			_, _ = fmt.Fprintln(stdout, "approved")
+			return status.Success, nil
+		}
+		_, _ = fmt.Fprintln(stdout, "rejected")
+		return status.Failed, nil
+	case <-timeout:
+		log.Printf("task %s was not approved in time", task.ID())
+		_, _ = fmt.Fprintln(stdout, "rejected: approval timed out")
+		return status.Failed, nil
+	case <-ctx.Done():
+		return status.Failed, nil
+	}
+}
@@ -0,0 +1,154 @@
+package executor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+type CacheableTasker interface {
+	Tasker
+	CacheKey() (string, bool, error)
+}
+
+type ArtifactTasker interface {
+	CacheableTasker
+	Artifacts() []string
+	SaveArtifact(name string, w io.Writer) error
+	RestoreArtifact(name string, r io.Reader) error
+}
+
+type CachedResult struct {
+	Stdout    []byte
+	Stderr    []byte
+	Outputs   map[string]string
+	Artifacts map[string][]byte
+}
+
+type Cache interface {
+	Load(key string) (CachedResult, bool, error)
+	Store(key string, result CachedResult) error
+}
+
+func WithCache(cache Cache) func(*Executor) {
+	return func(e *Executor) {
+		e.cache = cache
+	}
+}
+
+func cacheable(task Tasker) (CacheableTasker, bool) {
+	for _, unit := range planner.Unwrap(task) {
+		if unit, ok := unit.(CacheableTasker); ok {
+			return unit, true
+		}
+	}
+	return nil, false
+}
+
+func (e *Executor) cached(ctx context.Context, task Tasker, unit CacheableTasker, stdout, stderr io.Writer) (status.Type, error) {
+	key, ok, err := unit.CacheKey()
+	if err != nil {
+		log.Printf("could not compute cache key for task %s: %s", task.ID(), err)
+	}
+	if !ok || err != nil {
+		return e.dispatch(ctx, task, stdout, stderr)
+	}
+
+	result, ok, err := e.cache.Load(key)
+	if err != nil {
+		log.Printf("could not load cached result for task %s: %s", task.ID(), err)
+	}
+	if ok {
+		err = restoreArtifacts(unit, result.Artifacts)
+		if err == nil {
+			log.Printf("task %s restored from cache", task.ID())
+			return e.restore(task, result, stdout, stderr)
+		}
+		log.Printf("could not restore task %s from cache: %s", task.ID(), err)
+	}
+
+	capturedStdout, capturedStderr := &bytes.Buffer{}, &bytes.Buffer{}
+	finalState, err := e.dispatch(
+		ctx,
+		task,
+		io.MultiWriter(stdout, capturedStdout),
+		io.MultiWriter(stderr, capturedStderr),
+	)
+	if finalState != status.Success || err != nil || ctx.Err() != nil {
+		return finalState, err
+	}
+
+	artifacts, storeErr := saveArtifacts(unit)
+	if storeErr != nil {
+		log.Printf("could not cache result for task %s: %s", task.ID(), storeErr)
+		return finalState, err
+	}
+
+	storeErr = e.cache.Store(key, CachedResult{
+		Stdout:    capturedStdout.Bytes(),
+		Stderr:    capturedStderr.Bytes(),
+		Outputs:   e.outputs.Values(task.ID()),
+		Artifacts: artifacts,
+	})
+	if storeErr != nil {
+		log.Printf("could not cache result for task %s: %s", task.ID(), storeErr)
+	}
+	return finalState, err
+}
+
+func (e *Executor) restore(task Tasker, result CachedResult, stdout, stderr io.Writer) (status.Type, error) {
+	_, err := stdout.Write(result.Stdout)
+	if err != nil {
+		return status.Errored, fmt.Errorf("could not replay cached stdout: %s", err)
+	}
+	_, err = stderr.Write(result.Stderr)
+	if err != nil {
+		return status.Errored, fmt.Errorf("could not replay cached stderr: %s", err)
+	}
+	for key, value := range result.Outputs {
+		e.outputs.Set(task, key, value)
+	}
+	return status.Success, nil
+}
+
+func saveArtifacts(unit CacheableTasker) (map[string][]byte, error) {
+	task, ok := unit.(ArtifactTasker)
+	if !ok {
+		return nil, nil
+	}
+
+	artifacts := map[string][]byte{}
+	for _, name := range task.Artifacts() {
+		contents := &bytes.Buffer{}
+		err := task.SaveArtifact(name, contents)
+		if err != nil {
+			return nil, fmt.Errorf("could not save artifact %s: %s", name, err)
+		}
+		artifacts[name] = contents.Bytes()
+	}
+	return artifacts, nil
+}
+
+func restoreArtifacts(unit CacheableTasker, artifacts map[string][]byte) error {
+	task, ok := unit.(ArtifactTasker)
+	if !ok {
+		return nil
+	}
+
+	for _, name := range task.Artifacts() {
+		contents, ok := artifacts[name]
+		if !ok {
+			return fmt.Errorf("artifact %s was not cached", name)
+		}
+		err := task.RestoreArtifact(name, bytes.NewReader(contents))
+		if err != nil {
+			return fmt.Errorf("could not restore artifact %s: %s", name, err)
+		}
+	}
+	return nil
+}
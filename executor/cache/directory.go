@@ -0,0 +1,166 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/jtarchie/dothings/executor"
+)
+
+type entry struct {
+	Stdout    string            `json:"stdout"`
+	Stderr    string            `json:"stderr"`
+	Outputs   map[string]string `json:"outputs,omitempty"`
+	Artifacts map[string]string `json:"artifacts,omitempty"`
+}
+
+type directory struct {
+	path string
+}
+
+var _ executor.Cache = &directory{}
+
+func NewDirectory(path string) (*directory, error) {
+	for _, dir := range []string{"objects", "keys"} {
+		err := os.MkdirAll(filepath.Join(path, dir), 0755)
+		if err != nil {
+			return nil, fmt.Errorf("could not create cache directory: %s", err)
+		}
+	}
+
+	return &directory{
+		path: path,
+	}, nil
+}
+
+func (d *directory) Load(key string) (executor.CachedResult, bool, error) {
+	contents, err := ioutil.ReadFile(d.keyPath(key))
+	if os.IsNotExist(err) {
+		return executor.CachedResult{}, false, nil
+	}
+	if err != nil {
+		return executor.CachedResult{}, false, fmt.Errorf("could not read cache entry: %s", err)
+	}
+
+	var e entry
+	err = json.Unmarshal(contents, &e)
+	if err != nil {
+		return executor.CachedResult{}, false, fmt.Errorf("could not decode cache entry: %s", err)
+	}
+
+	stdout, err := d.object(e.Stdout)
+	if err != nil {
+		return executor.CachedResult{}, false, err
+	}
+	stderr, err := d.object(e.Stderr)
+	if err != nil {
+		return executor.CachedResult{}, false, err
+	}
+
+	var artifacts map[string][]byte
+	for name, digest := range e.Artifacts {
+		if artifacts == nil {
+			artifacts = map[string][]byte{}
+		}
+		artifacts[name], err = d.object(digest)
+		if err != nil {
+			return executor.CachedResult{}, false, err
+		}
+	}
+
+	return executor.CachedResult{
+		Stdout:    stdout,
+		Stderr:    stderr,
+		Outputs:   e.Outputs,
+		Artifacts: artifacts,
+	}, true, nil
+}
+
+func (d *directory) Store(key string, result executor.CachedResult) error {
+	stdout, err := d.write(result.Stdout)
+	if err != nil {
+		return err
+	}
+	stderr, err := d.write(result.Stderr)
+	if err != nil {
+		return err
+	}
+
+	var artifacts map[string]string
+	for name, contents := range result.Artifacts {
+		if artifacts == nil {
+			artifacts = map[string]string{}
+		}
+		artifacts[name], err = d.write(contents)
+		if err != nil {
+			return err
+		}
+	}
+
+	contents, err := json.Marshal(entry{
+		Stdout:    stdout,
+		Stderr:    stderr,
+		Outputs:   result.Outputs,
+		Artifacts: artifacts,
+	})
+	if err != nil {
+		return fmt.Errorf("could not encode cache entry: %s", err)
+	}
+	return d.atomically(d.keyPath(key), contents)
+}
+
+func (d *directory) object(digest string) ([]byte, error) {
+	contents, err := ioutil.ReadFile(filepath.Join(d.path, "objects", digest))
+	if err != nil {
+		return nil, fmt.Errorf("could not read cached object: %s", err)
+	}
+	if hash(contents) != digest {
+		return nil, fmt.Errorf("cached object %s is corrupt", digest)
+	}
+	return contents, nil
+}
+
+func (d *directory) write(contents []byte) (string, error) {
+	digest := hash(contents)
+	path := filepath.Join(d.path, "objects", digest)
+	if _, err := os.Stat(path); err == nil {
+		return digest, nil
+	}
+	return digest, d.atomically(path, contents)
+}
+
+func (d *directory) atomically(path string, contents []byte) error {
+	file, err := ioutil.TempFile(d.path, "tmp-")
+	if err != nil {
+		return fmt.Errorf("could not write to cache: %s", err)
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(contents)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("could not write to cache: %s", err)
+	}
+
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		return fmt.Errorf("could not write to cache: %s", err)
+	}
+	return nil
+}
+
+func (d *directory) keyPath(key string) string {
+	return filepath.Join(d.path, "keys", hash([]byte(key)))
+}
+
+func hash(contents []byte) string {
+	sum := sha256.Sum256(contents)
+	return hex.EncodeToString(sum[:])
+}
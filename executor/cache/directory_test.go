@@ -0,0 +1,84 @@
+package cache_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/executor/cache"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Directory", func() {
+	var dir string
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "")
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	AfterEach(func() {
+		_ = os.RemoveAll(dir)
+	})
+
+	It("misses for keys that were never stored", func() {
+		results, err := cache.NewDirectory(dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		_, ok, err := results.Load("unknown")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeFalse())
+	})
+
+	It("loads stored results across instances", func() {
+		results, err := cache.NewDirectory(dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		stored := executor.CachedResult{
+			Stdout:    []byte("built\n"),
+			Stderr:    []byte("warning\n"),
+			Outputs:   map[string]string{"version": "1.2.3"},
+			Artifacts: map[string][]byte{"binary": []byte("compiled")},
+		}
+		Expect(results.Store("key", stored)).To(Succeed())
+
+		results, err = cache.NewDirectory(dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		loaded, ok, err := results.Load("key")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(ok).To(BeTrue())
+		Expect(loaded).To(Equal(stored))
+	})
+
+	It("stores identical logs once", func() {
+		results, err := cache.NewDirectory(dir)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(results.Store("a", executor.CachedResult{Stdout: []byte("same"), Stderr: []byte("same")})).To(Succeed())
+		Expect(results.Store("b", executor.CachedResult{Stdout: []byte("same"), Stderr: []byte("same")})).To(Succeed())
+
+		objects, err := ioutil.ReadDir(filepath.Join(dir, "objects"))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(objects).To(HaveLen(1))
+	})
+
+	It("errors when a stored object was corrupted", func() {
+		results, err := cache.NewDirectory(dir)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(results.Store("key", executor.CachedResult{Stdout: []byte("built")})).To(Succeed())
+
+		objects, err := filepath.Glob(filepath.Join(dir, "objects", "*"))
+		Expect(err).NotTo(HaveOccurred())
+		for _, object := range objects {
+			Expect(ioutil.WriteFile(object, []byte("tampered"), 0644)).To(Succeed())
+		}
+
+		_, ok, err := results.Load("key")
+		Expect(err).To(MatchError(ContainSubstring("is corrupt")))
+		Expect(ok).To(BeFalse())
+	})
+})
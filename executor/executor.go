@@ -1,10 +1,12 @@
 package executor
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"log"
 	"runtime"
+	"sync"
 	"time"
 
 	"github.com/jtarchie/dothings/planner"
@@ -16,97 +18,424 @@ type Tasker interface {
 	Execute(io.Writer, io.Writer) (status.Type, error)
 }
 
+type ContextTasker interface {
+	Tasker
+	ExecuteContext(context.Context, io.Writer, io.Writer) (status.Type, error)
+}
+
 type Writer interface {
 	GetWriter(Tasker) (io.Writer, io.Writer)
 	GetString(Tasker) (string, string)
 }
 
+type Dispatcher interface {
+	Dispatch(context.Context, Tasker, io.Writer, io.Writer) (status.Type, error)
+}
+
 type StringerWriter interface {
 	fmt.Stringer
 	io.Writer
 }
 
 type Executor struct {
-	plan   planner.Step
-	writer Writer
-	stater status.Stater
+	plan       planner.Step
+	writer     Writer
+	stater     status.Stater
+	outputs    status.Outputs
+	locks      *Locks
+	dispatcher Dispatcher
+	cache      Cache
+
+	workers   int
+	tagLimits map[string]int
+
+	lock    sync.Mutex
+	cancels map[string]context.CancelFunc
+	gates   map[string]chan bool
+	aborted bool
+}
+
+type option func(*Executor)
+
+func WithLocks(locks *Locks) func(*Executor) {
+	return func(e *Executor) {
+		e.locks = locks
+	}
+}
+
+func WithDispatcher(dispatcher Dispatcher) func(*Executor) {
+	return func(e *Executor) {
+		e.dispatcher = dispatcher
+	}
+}
+
+func WithWorkers(workers int) func(*Executor) {
+	return func(e *Executor) {
+		e.workers = workers
+	}
+}
+
+func WithTagLimit(tag string, limit int) func(*Executor) {
+	return func(e *Executor) {
+		e.tagLimits[tag] = limit
+	}
+}
+
+type queued struct {
+	task     Tasker
+	branch   string
+	delay    time.Duration
+	ctx      context.Context
+	cancel   context.CancelFunc
+	decided  bool
+	decision status.Type
+	reason   error
 }
 
 func (e *Executor) Wait() status.Type {
-	queue := make(chan Tasker)
+	queue := newPool(e.workers, e.tagLimits)
 	statuses := e.stater
 
 	go func() {
-		for task := range queue {
-			go func(task Tasker) {
-				stdout, stderr := e.writer.GetWriter(task)
-
-				err := statuses.Add(task, status.Running)
-				if err != nil {
-					log.Printf("could not start task %s to state Running", task.ID())
-					return
-				}
-				finalState, err := task.Execute(stdout, stderr)
-				if err != nil {
-					log.Printf("task failed execution: %s", err)
-					finalState = status.Errored
-				}
-				err = statuses.Add(task, finalState)
-				if err != nil {
-					log.Printf("could not finished task %s to state %d", task.ID(), finalState)
-				}
-			}(task)
+		for {
+			q, ok := queue.pop()
+			if !ok {
+				return
+			}
+			go func(q queued) {
+				defer queue.done(q.task)
+				e.run(q)
+			}(q)
 			runtime.Gosched()
 		}
 	}()
 
+	defer e.cancelAll()
+
+	events, stop := statuses.Watch()
+	defer stop()
+
 	for {
 		tasks := e.plan.Next(statuses)
 
 		if 0 < len(tasks) {
 			for _, task := range tasks {
+				if e.isAborted() && !planner.InHook(e.plan, task.ID()) {
+					e.abandon(task)
+					continue
+				}
+				if !e.locks.acquire(planner.Holds(e.plan, task), statuses) {
+					continue
+				}
+
 				err := statuses.Add(task, status.Unstarted)
 				if err != nil {
 					log.Printf("could not queue task %s to state Unstarted", task.ID())
 					continue
 				}
-				queue <- task
+				ctx, cancel := e.track(task)
+				q := queued{
+					task:   task,
+					branch: planner.Branch(e.plan, task.ID()),
+					delay: planner.RetryDelay(
+						e.plan,
+						task,
+						len(statuses.Get(task)),
+						e.finishedAt,
+						time.Now(),
+					),
+					ctx:    ctx,
+					cancel: cancel,
+				}
+				if _, ok := gated(task); ok || q.delay > 0 {
+					go e.stage(queue, q)
+					continue
+				}
+				queue.push(q)
 			}
 		}
 
+		for _, task := range planner.Cancelled(e.plan, statuses) {
+			e.cancel(task)
+		}
+
+		e.locks.release(statuses)
+
 		if len(tasks) == 0 {
 			v := e.plan.State(statuses)
 			switch v {
 			case status.Running, status.Unstarted:
-				break
+				if e.isAborted() && e.idle() {
+					queue.close()
+					return status.Failed
+				}
 			default:
-				close(queue)
+				queue.close()
 				return v
 			}
 		}
-		time.Sleep(100 * time.Millisecond)
+
+		select {
+		case _, ok := <-events:
+			if !ok {
+				events = nil
+			}
+			events = drain(events)
+		case <-time.After(100 * time.Millisecond):
+		}
+	}
+}
+
+func drain(events <-chan status.Event) <-chan status.Event {
+	for events != nil {
+		select {
+		case _, ok := <-events:
+			if !ok {
+				return nil
+			}
+		default:
+			return events
+		}
+	}
+	return nil
+}
+
+func (e *Executor) stage(queue *pool, q queued) {
+	task, ctx := q.task, q.ctx
+	if q.delay > 0 {
+		select {
+		case <-time.After(q.delay):
+		case <-ctx.Done():
+		}
+	}
+
+	if gate, ok := gated(task); ok && ctx.Err() == nil {
+		err := e.stater.Add(task, status.Running)
+		if err != nil {
+			log.Printf("could not start task %s to state Running", task.ID())
+			e.untrack(task, q.cancel)
+			return
+		}
+
+		stdout, _ := e.writer.GetWriter(task)
+		q.decision, q.reason = e.await(ctx, task, gate, stdout)
+		q.decided = true
+	}
+
+	queue.push(q)
+}
+
+func (e *Executor) run(q queued) {
+	task, ctx := q.task, q.ctx
+	stdout, stderr := e.writer.GetWriter(task)
+
+	var err error
+	if !q.decided {
+		err = e.stater.Add(task, status.Running)
+		if err != nil {
+			log.Printf("could not start task %s to state Running", task.ID())
+			e.untrack(task, q.cancel)
+			return
+		}
+	}
+
+	var finalState status.Type
+	if q.decided {
+		finalState, err = q.decision, q.reason
+	} else if ctx.Err() == nil {
+		finalState, err = e.execute(ctx, task, stdout, stderr)
+	} else if contextual(task) {
+		finalState, err = Execute(ctx, task, stdout, stderr)
+	}
+	cancelled := ctx.Err() != nil
+	e.untrack(task, q.cancel)
+
+	var reason error
+	switch {
+	case cancelled:
+		log.Printf("task %s was cancelled", task.ID())
+		finalState, reason = status.Failed, fmt.Errorf("task was cancelled")
+	case err != nil:
+		reason = err
+		if _, ok := status.ExitCode(err); !ok || finalState != status.Failed {
+			log.Printf("task failed execution: %s", err)
+			finalState = status.Errored
+		}
+	}
+	if reason != nil {
+		err = e.stater.AddError(task, reason)
+		if err != nil {
+			log.Printf("could not record error for task %s: %s", task.ID(), err)
+		}
+	}
+
+	err = e.stater.Add(task, finalState)
+	if err != nil {
+		log.Printf("could not finished task %s to state %d", task.ID(), finalState)
+	}
+}
+
+func (e *Executor) execute(ctx context.Context, task Tasker, stdout, stderr io.Writer) (status.Type, error) {
+	if unit, ok := cacheable(task); ok && e.cache != nil {
+		return e.cached(ctx, task, unit, stdout, stderr)
+	}
+	return e.dispatch(ctx, task, stdout, stderr)
+}
+
+func (e *Executor) dispatchable(task Tasker) bool {
+	if _, generator := task.(planner.Step); generator {
+		return false
+	}
+	return !planner.InGenerated(e.plan, task.ID())
+}
+
+func (e *Executor) dispatch(ctx context.Context, task Tasker, stdout, stderr io.Writer) (status.Type, error) {
+	if e.dispatcher != nil && e.dispatchable(task) {
+		return e.dispatcher.Dispatch(ctx, task, stdout, stderr)
+	}
+	return Execute(ctx, task, stdout, stderr)
+}
+
+func contextual(task Tasker) bool {
+	for _, unit := range planner.Unwrap(task) {
+		if _, ok := unit.(ContextTasker); ok {
+			return true
+		}
+	}
+	return false
+}
+
+func Execute(ctx context.Context, task Tasker, stdout, stderr io.Writer) (status.Type, error) {
+	for _, unit := range planner.Unwrap(task) {
+		if unit, ok := unit.(ContextTasker); ok {
+			return unit.ExecuteContext(ctx, stdout, stderr)
+		}
+	}
+	return task.Execute(stdout, stderr)
+}
+
+func (e *Executor) track(task Tasker) (context.Context, context.CancelFunc) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	ctx, cancel := context.WithCancel(status.WithOutputs(context.Background(), e.outputs))
+	e.cancels[task.ID()] = cancel
+	return ctx, cancel
+}
+
+func (e *Executor) untrack(task Tasker, cancel context.CancelFunc) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	delete(e.cancels, task.ID())
+	cancel()
+}
+
+func (e *Executor) finishedAt(task planner.Tasker, attempt int) (time.Time, bool) {
+	if attempts := e.stater.Attempts(task); len(attempts) >= attempt {
+		return attempts[attempt-1].FinishedAt()
+	}
+	return time.Time{}, false
+}
+
+func (e *Executor) cancel(task planner.Tasker) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	if cancel, ok := e.cancels[task.ID()]; ok {
+		cancel()
+	}
+}
+
+func (e *Executor) Abort() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	e.aborted = true
+	for id, cancel := range e.cancels {
+		if !planner.InHook(e.plan, id) {
+			cancel()
+		}
+	}
+}
+
+func (e *Executor) isAborted() bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return e.aborted
+}
+
+func (e *Executor) idle() bool {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	return len(e.cancels) == 0
+}
+
+func (e *Executor) abandon(task Tasker) {
+	for _, s := range []status.Type{status.Unstarted, status.Running} {
+		err := e.stater.Add(task, s)
+		if err != nil {
+			log.Printf("could not abandon task %s: %s", task.ID(), err)
+			return
+		}
+	}
+	err := e.stater.AddError(task, fmt.Errorf("build was aborted"))
+	if err != nil {
+		log.Printf("could not record error for task %s: %s", task.ID(), err)
+	}
+	err = e.stater.Add(task, status.Failed)
+	if err != nil {
+		log.Printf("could not abandon task %s: %s", task.ID(), err)
+	}
+}
+
+func (e *Executor) cancelAll() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+
+	for _, cancel := range e.cancels {
+		cancel()
 	}
 }
 
 func NewExecutor(
 	plan planner.Step,
 	writer Writer,
+	options ...option,
 ) *Executor {
-	return &Executor{
-		plan:   plan,
-		writer: writer,
-		stater: status.NewStatuses(),
-	}
+	return NewExecutorWithStater(plan, writer, status.NewStatuses(), options...)
 }
 
 func NewExecutorWithStater(
 	plan planner.Step,
 	writer Writer,
 	stater status.Stater,
+	options ...option,
+) *Executor {
+	return NewExecutorWithOutputs(plan, writer, stater, status.NewOutputs(), options...)
+}
+
+func NewExecutorWithOutputs(
+	plan planner.Step,
+	writer Writer,
+	stater status.Stater,
+	outputs status.Outputs,
+	options ...option,
 ) *Executor {
-	return &Executor{
-		plan:   plan,
-		writer: writer,
-		stater: stater,
+	e := &Executor{
+		plan:      plan,
+		writer:    writer,
+		stater:    stater,
+		outputs:   outputs,
+		locks:     NewLocks(nil),
+		tagLimits: map[string]int{},
+		cancels:   map[string]context.CancelFunc{},
+		gates:     map[string]chan bool{},
+	}
+	for _, o := range options {
+		o(e)
 	}
+	return e
 }
@@ -1,11 +1,15 @@
 package executor_test
 
 import (
+	"context"
 	"fmt"
 	"io"
+	"io/ioutil"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/jtarchie/dothings/planner"
 	"github.com/jtarchie/dothings/status"
 
 	. "github.com/onsi/ginkgo"
@@ -77,3 +81,181 @@ func (i *blockingTask) Execute(stdout io.Writer, stderr io.Writer) (status.Type,
 	<-i.wait
 	return status.Success, nil
 }
+
+type cancelableTask struct {
+	task
+}
+
+func (i cancelableTask) Execute(stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	return i.ExecuteContext(context.Background(), stdout, stderr)
+}
+
+func (i cancelableTask) ExecuteContext(ctx context.Context, stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	select {
+	case <-ctx.Done():
+		_, _ = fmt.Fprintf(stdout, "cancelled %s\n", string(i.task))
+		return status.Errored, ctx.Err()
+	case <-time.After(10 * time.Second):
+		return i.task.Execute(stdout, stderr)
+	}
+}
+
+type flakyTask struct {
+	task
+	failures int
+}
+
+func (i *flakyTask) Execute(stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	_, _ = i.task.Execute(stdout, stderr)
+	if i.failures > 0 {
+		i.failures--
+		return status.Errored, nil
+	}
+	return status.Success, nil
+}
+
+type discoveringTask struct {
+	task
+	packages []string
+}
+
+func (i discoveringTask) Generate(stdout io.Writer, stderr io.Writer) (func(planner.Planner) error, error) {
+	_, _ = i.task.Execute(stdout, stderr)
+	return func(plan planner.Planner) error {
+		return plan.Parallel(func(plan planner.Planner) error {
+			for _, name := range i.packages {
+				plan.Task(task(name))
+			}
+			return nil
+		})
+	}, nil
+}
+
+type versionTask struct {
+	task
+	version string
+}
+
+func (i versionTask) ExecuteContext(ctx context.Context, stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	outputs, _ := status.OutputsFromContext(ctx)
+	outputs.Set(i, "version", i.version)
+	return i.task.Execute(stdout, stderr)
+}
+
+type releaseTask struct {
+	task
+	from string
+}
+
+func (i releaseTask) ExecuteContext(ctx context.Context, stdout io.Writer, _ io.Writer) (status.Type, error) {
+	outputs, _ := status.OutputsFromContext(ctx)
+	version, ok := outputs.Get(i.from, "version")
+	if !ok {
+		return status.Failed, nil
+	}
+	_, _ = fmt.Fprintf(stdout, "released %s\n", version)
+	return status.Success, nil
+}
+
+type concurrency struct {
+	sync.Mutex
+	running int
+	max     int
+}
+
+func (c *concurrency) enter() {
+	c.Lock()
+	defer c.Unlock()
+	c.running++
+	if c.running > c.max {
+		c.max = c.running
+	}
+}
+
+func (c *concurrency) exit() {
+	c.Lock()
+	defer c.Unlock()
+	c.running--
+}
+
+func (c *concurrency) Max() int {
+	c.Lock()
+	defer c.Unlock()
+	return c.max
+}
+
+type deployTask struct {
+	task
+	locks   []string
+	tags    []string
+	tracker *concurrency
+}
+
+func (i deployTask) Locks() []string {
+	return i.locks
+}
+
+func (i deployTask) Tags() []string {
+	return i.tags
+}
+
+func (i deployTask) Execute(stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	i.tracker.enter()
+	defer i.tracker.exit()
+	time.Sleep(200 * time.Millisecond)
+	return i.task.Execute(stdout, stderr)
+}
+
+type cacheableTask struct {
+	versionTask
+	key  string
+	runs *int
+}
+
+func (i cacheableTask) CacheKey() (string, bool, error) {
+	if i.key == "broken" {
+		return "", false, fmt.Errorf("no key")
+	}
+	return i.key, i.key != "", nil
+}
+
+func (i cacheableTask) ExecuteContext(ctx context.Context, stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	*i.runs++
+	finalState, err := i.versionTask.ExecuteContext(ctx, stdout, stderr)
+	_, _ = fmt.Fprintln(stderr, "warning")
+	return finalState, err
+}
+
+type failingCacheableTask struct {
+	cacheableTask
+}
+
+func (i failingCacheableTask) ExecuteContext(ctx context.Context, stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	_, _ = i.cacheableTask.ExecuteContext(ctx, stdout, stderr)
+	return status.Failed, nil
+}
+
+type artifactTask struct {
+	cacheableTask
+	volume *string
+}
+
+func (i artifactTask) Artifacts() []string {
+	return []string{"binary"}
+}
+
+func (i artifactTask) SaveArtifact(name string, w io.Writer) error {
+	_, err := io.WriteString(w, *i.volume)
+	return err
+}
+
+func (i artifactTask) RestoreArtifact(name string, r io.Reader) error {
+	contents, err := ioutil.ReadAll(r)
+	*i.volume = string(contents)
+	return err
+}
+
+func (i artifactTask) ExecuteContext(ctx context.Context, stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	*i.volume = "compiled " + i.ID()
+	return i.cacheableTask.ExecuteContext(ctx, stdout, stderr)
+}
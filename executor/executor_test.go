@@ -2,6 +2,9 @@ package executor_test
 
 import (
 	"fmt"
+	"io/ioutil"
+	"net/http/httptest"
+	"os"
 	"strings"
 	"time"
 
@@ -10,6 +13,7 @@ import (
 	"github.com/jtarchie/dothings/tasks"
 
 	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/executor/cache"
 	"github.com/jtarchie/dothings/planner"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -116,6 +120,649 @@ var _ = Describe("Tasker", func() {
 			Expect(executor.NewExecutor(plan, console).Wait()).To(Equal(status.Errored))
 			Expect(stdout).To(gbytes.Say("executed 1"))
 		})
+
+		It("records the error with the attempt", func() {
+			plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+				plan.Task(erroringTask{"1"})
+				return nil
+			})
+
+			statuses := status.NewStatuses()
+			Expect(executor.NewExecutorWithStater(plan, console, statuses).Wait()).To(Equal(status.Errored))
+
+			attempts := statuses.Attempts(erroringTask{"1"})
+			Expect(attempts).To(HaveLen(1))
+			Expect(attempts[0].Error).To(Equal("error"))
+			Expect(attempts[0].ExitCode).To(BeNil())
+		})
+
+		It("keeps a non-zero exit as failed and records the exit code", func() {
+			command := tasks.NewCommand("bash", "-c", "exit 3")
+			plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+				plan.Task(command)
+				return nil
+			})
+
+			statuses := status.NewStatuses()
+			Expect(executor.NewExecutorWithStater(plan, console, statuses).Wait()).To(Equal(status.Failed))
+
+			attempts := statuses.Attempts(command)
+			Expect(attempts).To(HaveLen(1))
+			Expect(attempts[0].Error).To(Equal("exit status 3"))
+			Expect(*attempts[0].ExitCode).To(Equal(3))
+		})
+	})
+
+	When("tasks are defined as a graph", func() {
+		It("runs tasks once their dependencies have succeeded", func() {
+			plan, _ := planner.NewGraph(func(graph planner.Grapher) error {
+				graph.Task(task("A"))
+				graph.Task(task("B"))
+				graph.Task(task("C")).DependsOn("A", "B")
+				return nil
+			})
+
+			Expect(executor.NewExecutor(plan, console).Wait()).To(Equal(status.Success))
+			Expect(stdout).To(gbytes.Say("executed C"))
+		})
+
+		It("does not run the dependents of a failed task", func() {
+			plan, _ := planner.NewGraph(func(graph planner.Grapher) error {
+				graph.Task(failingTask{"A"})
+				graph.Task(task("B"))
+				graph.Task(task("C")).DependsOn("A")
+				return nil
+			})
+
+			Expect(executor.NewExecutor(plan, console).Wait()).To(Equal(status.Failed))
+			Expect(stdout.String()).To(ContainSubstring("executed B"))
+			Expect(stdout.String()).NotTo(ContainSubstring("executed C"))
+		})
+	})
+
+	When("a step generates the rest of the plan", func() {
+		It("runs the generated tasks", func() {
+			plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+				plan.Generate(discoveringTask{task: "discover", packages: []string{"A", "B"}})
+				plan.Task(task("C"))
+				return nil
+			})
+
+			Expect(executor.NewExecutor(plan, console).Wait()).To(Equal(status.Success))
+			Expect(stdout).To(gbytes.Say("executed discover"))
+			Expect(stdout.String()).To(ContainSubstring("executed A"))
+			Expect(stdout.String()).To(ContainSubstring("executed B"))
+			Expect(stdout).To(gbytes.Say("executed C"))
+		})
+	})
+
+	When("a task reads the outputs of another task", func() {
+		It("shares values by task ID", func() {
+			plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+				plan.Task(versionTask{task: "A", version: "1.2.3"})
+				plan.Task(releaseTask{task: "B", from: "A"})
+				return nil
+			})
+
+			outputs := status.NewOutputs()
+			Expect(executor.NewExecutorWithOutputs(plan, console, status.NewStatuses(), outputs).Wait()).To(Equal(status.Success))
+			Expect(stdout).To(gbytes.Say("released 1.2.3"))
+			Expect(outputs.Values("A")).To(Equal(map[string]string{"version": "1.2.3"}))
+		})
+	})
+
+	When("tasks have a cache key", func() {
+		var (
+			dir     string
+			results executor.Cache
+		)
+
+		BeforeEach(func() {
+			var err error
+			dir, err = ioutil.TempDir("", "")
+			Expect(err).NotTo(HaveOccurred())
+
+			results, err = cache.NewDirectory(dir)
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		AfterEach(func() {
+			_ = os.RemoveAll(dir)
+		})
+
+		It("restores the logs and outputs of a previous successful run", func() {
+			runs := 0
+			build := func(writer executor.Writer) status.Outputs {
+				plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+					plan.Task(cacheableTask{versionTask: versionTask{task: "A", version: "1.2.3"}, key: "abc", runs: &runs})
+					plan.Task(releaseTask{task: "B", from: "A"})
+					return nil
+				})
+
+				outputs := status.NewOutputs()
+				Expect(executor.NewExecutorWithOutputs(plan, writer, status.NewStatuses(), outputs, executor.WithCache(results)).Wait()).To(Equal(status.Success))
+				return outputs
+			}
+
+			build(console)
+			writer := writers.NewInMemory()
+			outputs := build(writer)
+
+			Expect(runs).To(Equal(1))
+			Expect(outputs.Values("A")).To(Equal(map[string]string{"version": "1.2.3"}))
+			output, _ := writer.GetString(task("A"))
+			Expect(output).To(Equal("executed A\nwarning\n"))
+			output, _ = writer.GetString(task("B"))
+			Expect(output).To(Equal("released 1.2.3\n"))
+		})
+
+		It("does not cache failed runs", func() {
+			runs := 0
+			plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+				plan.Task(cacheableTask{versionTask: versionTask{task: "A"}, key: "abc", runs: &runs})
+				plan.Task(failingTask{"B"})
+				return nil
+			})
+			Expect(executor.NewExecutor(plan, console, executor.WithCache(results)).Wait()).To(Equal(status.Failed))
+
+			_, ok, err := results.Load("abc")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeTrue())
+
+			failing := 0
+			plan, _ = planner.NewSerial(func(plan planner.Planner) error {
+				plan.Task(failingCacheableTask{cacheableTask{versionTask: versionTask{task: "C"}, key: "def", runs: &failing}})
+				return nil
+			})
+			Expect(executor.NewExecutor(plan, console, executor.WithCache(results)).Wait()).To(Equal(status.Failed))
+
+			_, ok, err = results.Load("def")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		It("restores tasks inside a matrix", func() {
+			runs := 0
+			for i := 0; i < 2; i++ {
+				plan, _ := planner.NewMatrix(map[string][]string{"os": {"linux"}}, func(plan planner.Planner, _ map[string]string) error {
+					plan.Task(cacheableTask{versionTask: versionTask{task: "A"}, key: "abc", runs: &runs})
+					return nil
+				})
+				Expect(executor.NewExecutor(plan, console, executor.WithCache(results)).Wait()).To(Equal(status.Success))
+			}
+			Expect(runs).To(Equal(1))
+		})
+
+		It("restores the artifacts of a previous successful run", func() {
+			runs := 0
+			for i := 0; i < 2; i++ {
+				volume := ""
+				plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+					plan.Task(artifactTask{cacheableTask{versionTask: versionTask{task: "A"}, key: "abc", runs: &runs}, &volume})
+					return nil
+				})
+				Expect(executor.NewExecutor(plan, console, executor.WithCache(results)).Wait()).To(Equal(status.Success))
+				Expect(volume).To(Equal("compiled A"))
+			}
+			Expect(runs).To(Equal(1))
+		})
+
+		It("executes tasks that cannot be cached", func() {
+			runs := 0
+			for i := 0; i < 2; i++ {
+				plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+					plan.Task(cacheableTask{versionTask: versionTask{task: "A"}, runs: &runs})
+					return nil
+				})
+				Expect(executor.NewExecutor(plan, console, executor.WithCache(results)).Wait()).To(Equal(status.Success))
+			}
+			Expect(runs).To(Equal(2))
+
+			_, ok, err := results.Load("")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(ok).To(BeFalse())
+		})
+
+		It("executes the task when the key cannot be computed", func() {
+			runs := 0
+			for i := 0; i < 2; i++ {
+				plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+					plan.Task(cacheableTask{versionTask: versionTask{task: "A"}, key: "broken", runs: &runs})
+					return nil
+				})
+				Expect(executor.NewExecutor(plan, console, executor.WithCache(results)).Wait()).To(Equal(status.Success))
+			}
+			Expect(runs).To(Equal(2))
+		})
+	})
+
+	When("rerunning a finished build", func() {
+		It("only runs the failed tasks and the steps after them", func() {
+			plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+				plan.Task(task("A"))
+				plan.Task(&flakyTask{task: "B", failures: 1})
+				plan.Task(task("C"))
+				return nil
+			})
+
+			statuses := status.NewStatuses()
+			Expect(executor.NewExecutorWithStater(plan, console, statuses).Wait()).To(Equal(status.Errored))
+			Expect(statuses.Get(task("C"))).To(BeEmpty())
+
+			rerun := executor.Rerun(plan, statuses)
+			Expect(executor.NewExecutorWithStater(plan, console, rerun).Wait()).To(Equal(status.Success))
+			Expect(plan.State(rerun)).To(Equal(status.Success))
+
+			Expect(statuses.Get(task("A"))).To(Equal([]status.Type{status.Success}))
+			Expect(statuses.Get(task("B"))).To(Equal([]status.Type{status.Errored, status.Success}))
+			Expect(statuses.Get(task("C"))).To(Equal([]status.Type{status.Success}))
+			Expect(strings.Count(stdout.String(), "executed A")).To(Equal(1))
+		})
+	})
+
+	When("tasks share a lock", func() {
+		It("does not run them at the same time", func() {
+			tracker := &concurrency{}
+			plan, _ := planner.NewParallel(func(plan planner.Planner) error {
+				plan.Task(deployTask{task: "A", locks: []string{"staging"}, tracker: tracker})
+				plan.Task(deployTask{task: "B", locks: []string{"staging"}, tracker: tracker})
+				plan.Task(deployTask{task: "C", locks: []string{"staging"}, tracker: tracker})
+				return nil
+			})
+
+			Expect(executor.NewExecutor(plan, console).Wait()).To(Equal(status.Success))
+			Expect(tracker.Max()).To(Equal(1))
+			Expect(stdout.String()).To(ContainSubstring("executed A"))
+			Expect(stdout.String()).To(ContainSubstring("executed B"))
+			Expect(stdout.String()).To(ContainSubstring("executed C"))
+		})
+
+		It("allows as many holders as the capacity", func() {
+			tracker := &concurrency{}
+			plan, _ := planner.NewParallel(func(plan planner.Planner) error {
+				plan.Task(deployTask{task: "A", locks: []string{"staging"}, tracker: tracker})
+				plan.Task(deployTask{task: "B", locks: []string{"staging"}, tracker: tracker})
+				plan.Task(deployTask{task: "C", locks: []string{"staging"}, tracker: tracker})
+				return nil
+			})
+
+			locks := executor.NewLocks(map[string]int{"staging": 2})
+			Expect(executor.NewExecutor(plan, console, executor.WithLocks(locks)).Wait()).To(Equal(status.Success))
+			Expect(tracker.Max()).To(Equal(2))
+		})
+
+		It("holds a step lock until the whole step has finished", func() {
+			tracker := &concurrency{}
+			plan, _ := planner.NewParallel(func(plan planner.Planner) error {
+				for _, branch := range []string{"A", "B"} {
+					branch := branch
+					err := plan.Serial(func(plan planner.Planner) error {
+						plan.Task(deployTask{task: task(branch + "1"), tracker: tracker})
+						plan.Task(deployTask{task: task(branch + "2"), tracker: tracker})
+						return nil
+					}, planner.WithLocks("staging"))
+					if err != nil {
+						return err
+					}
+				}
+				return nil
+			})
+
+			Expect(executor.NewExecutor(plan, console).Wait()).To(Equal(status.Success))
+			Expect(tracker.Max()).To(Equal(1))
+
+			output := stdout.String()
+			first, second := "A", "B"
+			if strings.Index(output, "executed B1") < strings.Index(output, "executed A1") {
+				first, second = "B", "A"
+			}
+			Expect(strings.Index(output, "executed "+first+"2")).To(BeNumerically("<", strings.Index(output, "initializing "+second+"1")))
+		})
+
+		It("shares locks between executors", func() {
+			tracker := &concurrency{}
+			locks := executor.NewLocks(nil)
+			newPlan := func(name string) planner.Step {
+				plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+					plan.Task(deployTask{task: task(name), tracker: tracker})
+					return nil
+				}, planner.WithLocks("staging"))
+				return plan
+			}
+
+			done := make(chan status.Type)
+			for _, name := range []string{"A", "B"} {
+				go func(plan planner.Step) {
+					done <- executor.NewExecutor(plan, console, executor.WithLocks(locks)).Wait()
+				}(newPlan(name))
+			}
+			Eventually(done, 5).Should(Receive(Equal(status.Success)))
+			Eventually(done, 5).Should(Receive(Equal(status.Success)))
+			Expect(tracker.Max()).To(Equal(1))
+		})
+	})
+
+	When("the executor has a limited number of workers", func() {
+		It("does not run more tasks than workers", func() {
+			tracker := &concurrency{}
+			plan, _ := planner.NewParallel(func(plan planner.Planner) error {
+				for _, name := range []string{"A", "B", "C", "D", "E"} {
+					plan.Task(deployTask{task: task(name), tracker: tracker})
+				}
+				return nil
+			})
+
+			Expect(executor.NewExecutor(plan, console, executor.WithWorkers(2)).Wait()).To(Equal(status.Success))
+			Expect(tracker.Max()).To(Equal(2))
+		})
+
+		It("queues tasks fairly across parallel branches of unequal width", func() {
+			plan, _ := planner.NewParallel(func(plan planner.Planner) error {
+				err := plan.Parallel(func(plan planner.Planner) error {
+					for _, name := range []string{"A1", "A2", "A3", "A4"} {
+						plan.Task(task(name))
+					}
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				return plan.Serial(func(plan planner.Planner) error {
+					for _, name := range []string{"B1", "B2", "B3"} {
+						plan.Task(task(name))
+					}
+					return nil
+				})
+			})
+
+			Expect(executor.NewExecutor(plan, console, executor.WithWorkers(1)).Wait()).To(Equal(status.Success))
+			for _, name := range []string{"A1", "B1", "A2", "B2", "A3", "B3", "A4"} {
+				Expect(stdout).To(gbytes.Say("executed " + name))
+			}
+		})
+
+		It("limits the tasks running with a tag", func() {
+			docker, local := &concurrency{}, &concurrency{}
+			plan, _ := planner.NewParallel(func(plan planner.Planner) error {
+				for _, name := range []string{"A", "B", "C"} {
+					plan.Task(deployTask{task: task("docker " + name), tags: []string{"docker"}, tracker: docker})
+					plan.Task(deployTask{task: task("local " + name), tracker: local})
+				}
+				return nil
+			})
+
+			Expect(executor.NewExecutor(plan, console, executor.WithTagLimit("docker", 2)).Wait()).To(Equal(status.Success))
+			Expect(docker.Max()).To(Equal(2))
+			Expect(local.Max()).To(Equal(3))
+		})
+	})
+
+	When("a task waits for approval", func() {
+		var (
+			plan     planner.Step
+			statuses status.Stater
+			gate     *tasks.Approval
+		)
+
+		BeforeEach(func() {
+			gate = tasks.NewApproval("prod", 0)
+			plan, _ = planner.NewSerial(func(plan planner.Planner) error {
+				plan.Task(task("staging"))
+				plan.Task(gate)
+				plan.Task(task("prod"))
+				return nil
+			})
+			statuses = status.NewStatuses()
+		})
+
+		run := func(e *executor.Executor) chan status.Type {
+			done := make(chan status.Type)
+			go func() {
+				done <- e.Wait()
+			}()
+			Eventually(func() []status.Type {
+				return statuses.Get(gate)
+			}, 5).Should(Equal([]status.Type{status.Pending}))
+			return done
+		}
+
+		It("continues once approved", func() {
+			e := executor.NewExecutorWithStater(plan, console, statuses)
+			done := run(e)
+
+			Consistently(done).ShouldNot(Receive())
+			Expect(stdout.String()).NotTo(ContainSubstring("executed prod"))
+
+			Expect(e.Approve(gate.ID())).To(Succeed())
+			Eventually(done, 5).Should(Receive(Equal(status.Success)))
+			Expect(stdout.String()).To(ContainSubstring("executed prod"))
+		})
+
+		It("fails once rejected", func() {
+			e := executor.NewExecutorWithStater(plan, console, statuses)
+			done := run(e)
+
+			Expect(e.Reject(gate.ID())).To(Succeed())
+			Eventually(done, 5).Should(Receive(Equal(status.Failed)))
+			Expect(stdout.String()).NotTo(ContainSubstring("executed prod"))
+		})
+
+		It("rejects when not approved in time", func() {
+			gate = tasks.NewApproval("prod", 200*time.Millisecond)
+			plan, _ = planner.NewSerial(func(plan planner.Planner) error {
+				plan.Task(gate)
+				plan.Task(task("prod"))
+				return nil
+			})
+
+			e := executor.NewExecutorWithStater(plan, console, statuses)
+			done := run(e)
+			Eventually(done, 5).Should(Receive(Equal(status.Failed)))
+			Expect(stdout.String()).To(ContainSubstring("approval timed out"))
+		})
+
+		It("waits for gates inside a matrix", func() {
+			plan, _ = planner.NewMatrix(map[string][]string{"env": {"prod"}}, func(plan planner.Planner, _ map[string]string) error {
+				plan.Task(tasks.NewApproval("deploy", 0))
+				return nil
+			})
+
+			e := executor.NewExecutorWithStater(plan, console, statuses)
+			done := make(chan status.Type)
+			go func() {
+				done <- e.Wait()
+			}()
+
+			id := "approval: deploy [env=prod]"
+			Eventually(func() error {
+				return e.Approve(id)
+			}, 5).Should(Succeed())
+			Eventually(done, 5).Should(Receive(Equal(status.Success)))
+		})
+
+		It("does not hold a worker while waiting for approval", func() {
+			plan, _ = planner.NewParallel(func(plan planner.Planner) error {
+				plan.Task(gate)
+				plan.Task(task("build"))
+				return nil
+			})
+
+			e := executor.NewExecutorWithStater(plan, console, statuses, executor.WithWorkers(1))
+			done := run(e)
+
+			Eventually(func() []status.Type {
+				return statuses.Get(task("build"))
+			}, 5).Should(Equal([]status.Type{status.Success}))
+			Expect(statuses.Get(gate)).To(Equal([]status.Type{status.Pending}))
+
+			Expect(e.Approve(gate.ID())).To(Succeed())
+			Eventually(done, 5).Should(Receive(Equal(status.Success)))
+		})
+
+		It("errors for tasks that are not waiting", func() {
+			e := executor.NewExecutorWithStater(plan, console, statuses)
+			Expect(e.Approve("staging")).To(MatchError("task 'staging' is not waiting for approval"))
+			Expect(e.Reject("staging")).To(MatchError("task 'staging' is not waiting for approval"))
+		})
+	})
+
+	When("the executor is aborted", func() {
+		It("cancels running tasks and runs the hooks", func() {
+			plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+				err := plan.Parallel(func(plan planner.Planner) error {
+					plan.Task(cancelableTask{"A"})
+					plan.Task(cancelableTask{"B"})
+					return nil
+				})
+				if err != nil {
+					return err
+				}
+				plan.Task(task("C"))
+				return plan.Finally(func(plan planner.Planner) error {
+					plan.Task(task("cleanup"))
+					return nil
+				})
+			})
+
+			statuses := status.NewStatuses()
+			e := executor.NewExecutorWithStater(plan, console, statuses)
+			go func() {
+				defer GinkgoRecover()
+				Eventually(func() []status.Type {
+					return append(statuses.Get(task("A")), statuses.Get(task("B"))...)
+				}).Should(Equal([]status.Type{status.Running, status.Running}))
+				e.Abort()
+			}()
+
+			startTime := time.Now()
+			Expect(e.Wait()).To(Equal(status.Failed))
+			Expect(time.Since(startTime)).To(BeNumerically("<", 5*time.Second))
+
+			Expect(statuses.Get(task("A"))).To(Equal([]status.Type{status.Failed}))
+			Expect(statuses.Get(task("B"))).To(Equal([]status.Type{status.Failed}))
+			Expect(statuses.Get(task("C"))).To(BeEmpty())
+			Expect(statuses.Get(task("cleanup"))).To(Equal([]status.Type{status.Success}))
+			Expect(stdout.String()).To(ContainSubstring("executed cleanup"))
+		})
+
+		It("abandons tasks that have not started", func() {
+			plan, _ := planner.NewParallel(func(plan planner.Planner) error {
+				plan.Task(task("A"))
+				plan.Task(task("B"))
+				return plan.Failure(func(plan planner.Planner) error {
+					plan.Task(task("notify"))
+					return nil
+				})
+			})
+
+			statuses := status.NewStatuses()
+			e := executor.NewExecutorWithStater(plan, console, statuses)
+			e.Abort()
+
+			Expect(e.Wait()).To(Equal(status.Failed))
+			Expect(statuses.Get(task("A"))).To(Equal([]status.Type{status.Failed}))
+			Expect(statuses.Attempts(task("A"))[0].Error).To(Equal("build was aborted"))
+			Expect(statuses.Get(task("notify"))).To(Equal([]status.Type{status.Success}))
+			Expect(stdout.String()).NotTo(ContainSubstring("executed A"))
+		})
+	})
+
+	When("the statuses are shared over HTTP", func() {
+		It("runs the plan and reports its state to the server", func() {
+			plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+				plan.Task(task("A"))
+				plan.Task(failingTask{"B"})
+				return nil
+			})
+
+			statuses := status.NewStatuses()
+			server := httptest.NewServer(status.NewServer(statuses))
+			defer server.Close()
+
+			Expect(executor.NewExecutorWithStater(plan, console, status.NewClient(server.URL)).Wait()).To(Equal(status.Failed))
+			Expect(plan.State(statuses)).To(Equal(status.Failed))
+			Expect(statuses.Get(task("A"))).To(Equal([]status.Type{status.Success}))
+		})
+	})
+
+	When("a parallel step fails fast", func() {
+		It("cancels the running tasks and runs the failure hook", func() {
+			plan, _ := planner.NewParallel(func(plan planner.Planner) error {
+				plan.Task(failingTask{"A"})
+				plan.Task(cancelableTask{"B"})
+				return plan.Failure(func(plan planner.Planner) error {
+					plan.Task(task("C"))
+					return nil
+				})
+			}, planner.WithFailFast())
+
+			statuses := status.NewStatuses()
+			startTime := time.Now()
+			Expect(executor.NewExecutorWithStater(plan, console, statuses).Wait()).To(Equal(status.Failed))
+			Expect(time.Since(startTime)).To(BeNumerically("<", 5*time.Second))
+
+			Expect(stdout.String()).To(ContainSubstring("executed C"))
+			Eventually(stdout.String).Should(ContainSubstring("cancelled B"))
+			Eventually(func() []status.Type {
+				return statuses.Get(task("B"))
+			}).Should(Equal([]status.Type{status.Failed}))
+			Eventually(func() string {
+				attempts := statuses.Attempts(task("B"))
+				return attempts[len(attempts)-1].Error
+			}).Should(Equal("task was cancelled"))
+		})
+	})
+
+	When("a step is retried with a backoff", func() {
+		It("waits between attempts without blocking other branches", func() {
+			plan, _ := planner.NewParallel(func(plan planner.Planner) error {
+				err := plan.Serial(func(plan planner.Planner) error {
+					plan.Task(&flakyTask{task: "A", failures: 1})
+					return nil
+				}, planner.WithAttempts(2), planner.WithBackoff(planner.FixedBackoff(500*time.Millisecond)))
+				Expect(err).NotTo(HaveOccurred())
+
+				return plan.Serial(func(plan planner.Planner) error {
+					plan.Task(timedTask{task("200ms")})
+					plan.Task(task("B"))
+					return nil
+				})
+			})
+
+			statuses := status.NewStatuses()
+			startTime := time.Now()
+			Expect(executor.NewExecutorWithStater(plan, console, statuses).Wait()).To(Equal(status.Success))
+			Expect(time.Since(startTime)).To(BeNumerically(">=", 500*time.Millisecond))
+
+			Expect(stdout).To(gbytes.Say("executed A"))
+			Expect(stdout).To(gbytes.Say("executed B"))
+			Expect(stdout).To(gbytes.Say("executed A"))
+			Expect(statuses.Get(task("A"))).To(Equal([]status.Type{status.Errored, status.Success}))
+		})
+
+		It("does not hold a worker while waiting between attempts", func() {
+			plan, _ := planner.NewParallel(func(plan planner.Planner) error {
+				err := plan.Serial(func(plan planner.Planner) error {
+					plan.Task(&flakyTask{task: "A", failures: 1})
+					return nil
+				}, planner.WithAttempts(2), planner.WithBackoff(planner.FixedBackoff(time.Second)))
+				Expect(err).NotTo(HaveOccurred())
+
+				return plan.Serial(func(plan planner.Planner) error {
+					plan.Task(task("B"))
+					plan.Task(task("C"))
+					return nil
+				})
+			})
+
+			statuses := status.NewStatuses()
+			Expect(executor.NewExecutorWithStater(plan, console, statuses, executor.WithWorkers(1)).Wait()).To(Equal(status.Success))
+
+			Expect(stdout).To(gbytes.Say("executed A"))
+			Expect(stdout).To(gbytes.Say("executed C"))
+			Expect(stdout).To(gbytes.Say("executed A"))
+			Expect(statuses.Get(task("A"))).To(Equal([]status.Type{status.Errored, status.Success}))
+		})
 	})
 
 	When("a completion of one step starts a subsequent step", func() {
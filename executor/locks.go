@@ -0,0 +1,91 @@
+package executor
+
+import (
+	"sync"
+
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+type held struct {
+	hold   planner.Hold
+	stater status.Stater
+}
+
+type Locks struct {
+	sync.Mutex
+	capacities map[string]int
+	held       []held
+}
+
+func NewLocks(capacities map[string]int) *Locks {
+	return &Locks{
+		capacities: capacities,
+	}
+}
+
+func (l *Locks) capacity(name string) int {
+	if capacity, ok := l.capacities[name]; ok {
+		return capacity
+	}
+	return 1
+}
+
+func (l *Locks) acquire(holds []planner.Hold, stater status.Stater) bool {
+	l.Lock()
+	defer l.Unlock()
+
+	needed := []planner.Hold{}
+	counts := map[string]int{}
+	for _, hold := range holds {
+		if l.holding(hold, stater) {
+			continue
+		}
+		needed = append(needed, hold)
+		counts[hold.Name]++
+	}
+
+	for name, count := range counts {
+		if l.count(name)+count > l.capacity(name) {
+			return false
+		}
+	}
+
+	for _, hold := range needed {
+		l.held = append(l.held, held{hold: hold, stater: stater})
+	}
+	return true
+}
+
+func (l *Locks) holding(hold planner.Hold, stater status.Stater) bool {
+	for _, h := range l.held {
+		if h.stater == stater && h.hold.Same(hold) {
+			return true
+		}
+	}
+	return false
+}
+
+func (l *Locks) count(name string) int {
+	count := 0
+	for _, h := range l.held {
+		if h.hold.Name == name {
+			count++
+		}
+	}
+	return count
+}
+
+func (l *Locks) release(stater status.Stater) {
+	l.Lock()
+	defer l.Unlock()
+
+	remaining := []held{}
+	for _, h := range l.held {
+		if h.stater == stater && h.hold.Released(stater) {
+			continue
+		}
+		remaining = append(remaining, h)
+	}
+	l.held = remaining
+}
@@ -0,0 +1,261 @@
+package executor
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+type Build struct {
+	ID       string
+	Plan     planner.Step
+	Writer   Writer
+	Stater   status.Stater
+	executor *Executor
+	outputs  status.Outputs
+	options  []option
+
+	lock       sync.Mutex
+	started    bool
+	aborted    bool
+	finalState *status.Type
+	number     int
+	done       chan struct{}
+	rerun      *Build
+}
+
+func (b *Build) State() status.Type {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.finalState != nil {
+		return *b.finalState
+	}
+	if !b.started {
+		return status.Unstarted
+	}
+	return b.Plan.State(b.Stater)
+}
+
+func (b *Build) Aborted() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.aborted
+}
+
+func (b *Build) Finished() bool {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.finalState != nil
+}
+
+func (b *Build) Wait() status.Type {
+	<-b.done
+	return b.State()
+}
+
+func (b *Build) Approve(id string) error {
+	return b.executor.Approve(id)
+}
+
+func (b *Build) Reject(id string) error {
+	return b.executor.Reject(id)
+}
+
+func (b *Build) finish(s status.Type) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	b.finalState = &s
+	close(b.done)
+}
+
+type Manager struct {
+	limit int
+	locks *Locks
+
+	lock    sync.Mutex
+	count   int
+	builds  map[string]*Build
+	queue   []*Build
+	running int
+}
+
+func NewManager(limit int) *Manager {
+	return &Manager{
+		limit:  limit,
+		locks:  NewLocks(nil),
+		builds: map[string]*Build{},
+	}
+}
+
+func (m *Manager) Submit(
+	plan planner.Step,
+	writer Writer,
+	stater status.Stater,
+	options ...option,
+) *Build {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return m.submit(plan, writer, stater, status.NewOutputs(), options)
+}
+
+func (m *Manager) Rerun(id string, writer Writer) (*Build, error) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	build, ok := m.builds[id]
+	if !ok {
+		return nil, fmt.Errorf("build '%s' does not exist", id)
+	}
+
+	build.lock.Lock()
+	finished := build.finalState != nil
+	build.lock.Unlock()
+	if !finished {
+		return nil, fmt.Errorf("build '%s' has not finished", id)
+	}
+	if build.rerun != nil && !build.rerun.Finished() {
+		return nil, fmt.Errorf("build '%s' is already being rerun", id)
+	}
+
+	tasks := []planner.Tasker{}
+	_ = build.Plan.Tree().Walk(func(node planner.Tree, _ []planner.Tree) error {
+		if node.Type() == planner.Task {
+			tasks = append(tasks, node.Task())
+		}
+		return nil
+	})
+
+	ids, outputs := []string{}, status.NewOutputs()
+	for _, task := range tasks {
+		ids = append(ids, task.ID())
+		for key, value := range build.outputs.Values(task.ID()) {
+			outputs.Set(task, key, value)
+		}
+	}
+	stater := Rerun(build.Plan, status.NewCopy(build.Stater, ids))
+
+	for _, task := range tasks {
+		if len(stater.Get(task)) == 0 {
+			continue
+		}
+		stdout, stderr := writer.GetWriter(task)
+		previousStdout, previousStderr := build.Writer.GetString(task)
+		_, _ = io.WriteString(stdout, previousStdout)
+		if previousStderr != previousStdout {
+			_, _ = io.WriteString(stderr, previousStderr)
+		}
+	}
+
+	build.rerun = m.submit(build.Plan, writer, stater, outputs, build.options)
+	return build.rerun, nil
+}
+
+func (m *Manager) submit(
+	plan planner.Step,
+	writer Writer,
+	stater status.Stater,
+	outputs status.Outputs,
+	options []option,
+) *Build {
+	m.count++
+	build := &Build{
+		ID:       strconv.Itoa(m.count),
+		Plan:     plan,
+		Writer:   writer,
+		Stater:   stater,
+		executor: NewExecutorWithOutputs(plan, writer, stater, outputs, append([]option{WithLocks(m.locks)}, options...)...),
+		outputs:  outputs,
+		options:  options,
+		number:   m.count,
+		done:     make(chan struct{}),
+	}
+	m.builds[build.ID] = build
+	m.queue = append(m.queue, build)
+	m.schedule()
+
+	return build
+}
+
+func (m *Manager) List() []*Build {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	builds := make([]*Build, 0, len(m.builds))
+	for _, build := range m.builds {
+		builds = append(builds, build)
+	}
+	sort.Slice(builds, func(i, j int) bool {
+		return builds[i].number < builds[j].number
+	})
+	return builds
+}
+
+func (m *Manager) Get(id string) (*Build, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	build, ok := m.builds[id]
+	return build, ok
+}
+
+func (m *Manager) Abort(id string) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	build, ok := m.builds[id]
+	if !ok {
+		return fmt.Errorf("build '%s' does not exist", id)
+	}
+
+	build.lock.Lock()
+	finished, started := build.finalState != nil, build.started
+	build.aborted = true
+	build.lock.Unlock()
+
+	switch {
+	case finished:
+		return fmt.Errorf("build '%s' has already finished", id)
+	case started:
+		build.executor.Abort()
+	default:
+		for i, other := range m.queue {
+			if other == build {
+				m.queue = append(m.queue[:i], m.queue[i+1:]...)
+				break
+			}
+		}
+		build.finish(status.Failed)
+	}
+	return nil
+}
+
+func (m *Manager) schedule() {
+	for len(m.queue) > 0 && (m.limit <= 0 || m.running < m.limit) {
+		build := m.queue[0]
+		m.queue = m.queue[1:]
+		m.running++
+
+		build.lock.Lock()
+		build.started = true
+		build.lock.Unlock()
+
+		go func(build *Build) {
+			build.finish(build.executor.Wait())
+
+			m.lock.Lock()
+			defer m.lock.Unlock()
+			m.running--
+			m.schedule()
+		}(build)
+	}
+}
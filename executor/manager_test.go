@@ -0,0 +1,186 @@
+package executor_test
+
+import (
+	"time"
+
+	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/executor/writers"
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Manager", func() {
+	submit := func(manager *executor.Manager, tasks ...executor.Tasker) *executor.Build {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			for _, t := range tasks {
+				plan.Task(t)
+			}
+			return nil
+		})
+		return manager.Submit(plan, writers.NewInMemory(), status.NewStatuses())
+	}
+
+	It("runs builds concurrently with their own state and output", func() {
+		manager := executor.NewManager(0)
+
+		first := submit(manager, task("A"))
+		second := submit(manager, failingTask{"A"})
+
+		Expect(first.Wait()).To(Equal(status.Success))
+		Expect(second.Wait()).To(Equal(status.Failed))
+
+		stdout, _ := first.Writer.GetString(task("A"))
+		Expect(stdout).To(Equal("executed A\n"))
+		Expect(first.Stater.Get(task("A"))).To(Equal([]status.Type{status.Success}))
+		Expect(second.Stater.Get(task("A"))).To(Equal([]status.Type{status.Failed}))
+	})
+
+	It("does not run builds in the same serial group at the same time", func() {
+		manager := executor.NewManager(0)
+		tracker := &concurrency{}
+
+		builds := []*executor.Build{}
+		for _, name := range []string{"A", "B"} {
+			plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+				plan.Task(deployTask{task: task(name), tracker: tracker})
+				return nil
+			}, planner.WithLocks("staging"))
+			builds = append(builds, manager.Submit(plan, writers.NewInMemory(), status.NewStatuses()))
+		}
+
+		for _, build := range builds {
+			Expect(build.Wait()).To(Equal(status.Success))
+		}
+		Expect(tracker.Max()).To(Equal(1))
+	})
+
+	It("does not run builds of the same plan in one serial group at the same time", func() {
+		manager := executor.NewManager(0)
+		tracker := &concurrency{}
+
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(deployTask{task: task("A"), tracker: tracker})
+			return nil
+		}, planner.WithLocks("staging"))
+		first := manager.Submit(plan, writers.NewInMemory(), status.NewStatuses())
+		second := manager.Submit(plan, writers.NewInMemory(), status.NewStatuses())
+
+		Expect(first.Wait()).To(Equal(status.Success))
+		Expect(second.Wait()).To(Equal(status.Success))
+		Expect(tracker.Max()).To(Equal(1))
+	})
+
+	It("queues builds over the limit", func() {
+		manager := executor.NewManager(1)
+
+		blocking := newBlockingTask("blocking")
+		first := submit(manager, blocking)
+		second := submit(manager, task("B"))
+
+		Eventually(first.State).Should(Equal(status.Running))
+		Consistently(second.State, 200*time.Millisecond).Should(Equal(status.Unstarted))
+
+		close(blocking.wait)
+		Expect(first.Wait()).To(Equal(status.Success))
+		Expect(second.Wait()).To(Equal(status.Success))
+	})
+
+	It("lists and gets builds by ID", func() {
+		manager := executor.NewManager(0)
+
+		first := submit(manager, task("A"))
+		second := submit(manager, task("B"))
+
+		Expect(manager.List()).To(Equal([]*executor.Build{first, second}))
+		build, ok := manager.Get(second.ID)
+		Expect(ok).To(BeTrue())
+		Expect(build).To(Equal(second))
+
+		_, ok = manager.Get("unknown")
+		Expect(ok).To(BeFalse())
+		first.Wait()
+		second.Wait()
+	})
+
+	It("aborts running and queued builds", func() {
+		manager := executor.NewManager(1)
+
+		first := submit(manager, cancelableTask{"A"})
+		second := submit(manager, task("B"))
+		Eventually(func() []status.Type {
+			return first.Stater.Get(task("A"))
+		}).Should(Equal([]status.Type{status.Running}))
+
+		Expect(manager.Abort(second.ID)).To(Succeed())
+		Expect(second.Wait()).To(Equal(status.Failed))
+		Expect(second.Aborted()).To(BeTrue())
+		Expect(second.Stater.Get(task("B"))).To(BeEmpty())
+
+		Expect(manager.Abort(first.ID)).To(Succeed())
+		Expect(first.Wait()).To(Equal(status.Failed))
+
+		Expect(manager.Abort(first.ID)).To(MatchError("build '1' has already finished"))
+		Expect(manager.Abort("unknown")).To(MatchError("build 'unknown' does not exist"))
+	})
+
+	It("reruns the failed tasks of a finished build", func() {
+		manager := executor.NewManager(0)
+
+		blocking := newBlockingTask("blocking")
+		running := submit(manager, blocking)
+		_, err := manager.Rerun(running.ID, writers.NewInMemory())
+		Expect(err).To(MatchError("build '1' has not finished"))
+		close(blocking.wait)
+		running.Wait()
+
+		_, err = manager.Rerun("unknown", writers.NewInMemory())
+		Expect(err).To(MatchError("build 'unknown' does not exist"))
+
+		failed := submit(manager, task("A"), &flakyTask{task: "B", failures: 1})
+		Expect(failed.Wait()).To(Equal(status.Errored))
+
+		rerun, err := manager.Rerun(failed.ID, writers.NewInMemory())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(rerun.ID).To(Equal("3"))
+		Expect(rerun.Wait()).To(Equal(status.Success))
+
+		Expect(failed.State()).To(Equal(status.Errored))
+		Expect(rerun.Stater.Get(task("B"))).To(Equal([]status.Type{status.Success}))
+		stdout, _ := rerun.Writer.GetString(task("A"))
+		Expect(stdout).To(Equal("executed A\n"))
+		stdout, _ = rerun.Writer.GetString(task("B"))
+		Expect(stdout).To(Equal("executed B\n"))
+	})
+
+	It("leaves the original build untouched by its reruns", func() {
+		manager := executor.NewManager(0)
+
+		blocking := newBlockingTask("blocking")
+		failed := submit(manager, &flakyTask{task: "B", failures: 1}, blocking)
+		Expect(failed.Wait()).To(Equal(status.Errored))
+
+		rerun, err := manager.Rerun(failed.ID, writers.NewInMemory())
+		Expect(err).NotTo(HaveOccurred())
+		Eventually(func() []status.Type {
+			return rerun.Stater.Get(blocking)
+		}).Should(Equal([]status.Type{status.Running}))
+
+		_, err = manager.Rerun(failed.ID, writers.NewInMemory())
+		Expect(err).To(MatchError("build '1' is already being rerun"))
+
+		close(blocking.wait)
+		Expect(rerun.Wait()).To(Equal(status.Success))
+
+		Expect(failed.State()).To(Equal(status.Errored))
+		Expect(failed.Stater.Get(task("B"))).To(Equal([]status.Type{status.Errored}))
+		Expect(failed.Stater.Get(blocking)).To(BeEmpty())
+		stdout, _ := failed.Writer.GetString(task("B"))
+		Expect(stdout).To(Equal("executed B\n"))
+
+		again, err := manager.Rerun(failed.ID, writers.NewInMemory())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(again.Wait()).To(Equal(status.Success))
+	})
+})
@@ -0,0 +1,136 @@
+package executor
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/jtarchie/dothings/planner"
+)
+
+type TaggedTasker interface {
+	Tasker
+	Tags() []string
+}
+
+type pool struct {
+	sync.Mutex
+	cond *sync.Cond
+
+	size    int
+	limits  map[string]int
+	running int
+	tagged  map[string]int
+	pending map[string][]queued
+	order   []string
+	served  map[string]int
+	ticks   int
+	closed  bool
+}
+
+func newPool(size int, limits map[string]int) *pool {
+	p := &pool{
+		size:    size,
+		limits:  limits,
+		tagged:  map[string]int{},
+		pending: map[string][]queued{},
+		served:  map[string]int{},
+	}
+	p.cond = sync.NewCond(p)
+	return p
+}
+
+func (p *pool) push(q queued) {
+	p.Lock()
+	defer p.Unlock()
+
+	if _, ok := p.pending[q.branch]; !ok {
+		p.order = append(p.order, q.branch)
+	}
+	p.pending[q.branch] = append(p.pending[q.branch], q)
+	p.cond.Broadcast()
+}
+
+func (p *pool) pop() (queued, bool) {
+	p.Lock()
+	defer p.Unlock()
+
+	for {
+		if p.closed {
+			return queued{}, false
+		}
+
+		if q, ok := p.take(); ok {
+			p.running++
+			for _, tag := range tags(q.task) {
+				p.tagged[tag]++
+			}
+			return q, true
+		}
+
+		p.cond.Wait()
+	}
+}
+
+func (p *pool) take() (queued, bool) {
+	sort.SliceStable(p.order, func(i, j int) bool {
+		return p.served[p.order[i]] < p.served[p.order[j]]
+	})
+
+	for index, branch := range p.order {
+		for i, q := range p.pending[branch] {
+			if !p.available(q.task) {
+				continue
+			}
+
+			p.pending[branch] = append(p.pending[branch][:i], p.pending[branch][i+1:]...)
+			if len(p.pending[branch]) == 0 {
+				delete(p.pending, branch)
+				p.order = append(p.order[:index], p.order[index+1:]...)
+			}
+			p.ticks++
+			p.served[branch] = p.ticks
+			return q, true
+		}
+	}
+	return queued{}, false
+}
+
+func (p *pool) available(task Tasker) bool {
+	if p.size > 0 && p.running >= p.size {
+		return false
+	}
+	for _, tag := range tags(task) {
+		if limit, ok := p.limits[tag]; ok && p.tagged[tag] >= limit {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *pool) done(task Tasker) {
+	p.Lock()
+	defer p.Unlock()
+
+	p.running--
+	for _, tag := range tags(task) {
+		p.tagged[tag]--
+	}
+	p.cond.Broadcast()
+}
+
+func (p *pool) close() {
+	p.Lock()
+	defer p.Unlock()
+
+	p.closed = true
+	p.cond.Broadcast()
+}
+
+func tags(task Tasker) []string {
+	for _, unit := range planner.Unwrap(task) {
+		if unit, ok := unit.(TaggedTasker); ok {
+			return unit.Tags()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,367 @@
+package remote
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+type registration struct {
+	Name     string   `json:"name"`
+	Tags     []string `json:"tags"`
+	Capacity int      `json:"capacity"`
+}
+
+type registered struct {
+	Worker string `json:"worker"`
+}
+
+type work struct {
+	Assignment string `json:"assignment"`
+	TaskID     string `json:"task"`
+}
+
+type result struct {
+	Assignment string            `json:"assignment"`
+	Status     status.Type       `json:"status"`
+	Error      string            `json:"error,omitempty"`
+	ExitCode   *int              `json:"exit_code,omitempty"`
+	Outputs    map[string]string `json:"outputs,omitempty"`
+}
+
+type heartbeat struct {
+	Cancelled []string `json:"cancelled"`
+}
+
+type worker struct {
+	registration
+	running  int
+	lastSeen time.Time
+}
+
+type assignment struct {
+	id        string
+	task      executor.Tasker
+	stdout    io.Writer
+	stderr    io.Writer
+	worker    string
+	cancelled bool
+	done      chan result
+}
+
+type Coordinator struct {
+	poll    time.Duration
+	timeout time.Duration
+	mux     *http.ServeMux
+
+	lock        sync.Mutex
+	count       int
+	workers     map[string]*worker
+	pending     []*assignment
+	assignments map[string]*assignment
+	changed     chan struct{}
+}
+
+var _ executor.Dispatcher = &Coordinator{}
+
+func NewCoordinator() *Coordinator {
+	return NewCoordinatorWithPoll(time.Second)
+}
+
+func NewCoordinatorWithPoll(poll time.Duration) *Coordinator {
+	return NewCoordinatorWithTimeout(poll, 30*time.Second)
+}
+
+func NewCoordinatorWithTimeout(poll time.Duration, timeout time.Duration) *Coordinator {
+	c := &Coordinator{
+		poll:        poll,
+		timeout:     timeout,
+		mux:         http.NewServeMux(),
+		workers:     map[string]*worker{},
+		assignments: map[string]*assignment{},
+		changed:     make(chan struct{}),
+	}
+	c.mux.HandleFunc("/register", c.register)
+	c.mux.HandleFunc("/work", c.work)
+	c.mux.HandleFunc("/output", c.output)
+	c.mux.HandleFunc("/result", c.result)
+	c.mux.HandleFunc("/heartbeat", c.heartbeat)
+	return c
+}
+
+func (c *Coordinator) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c.mux.ServeHTTP(w, r)
+}
+
+func (c *Coordinator) Dispatch(ctx context.Context, task executor.Tasker, stdout, stderr io.Writer) (status.Type, error) {
+	c.lock.Lock()
+	c.count++
+	a := &assignment{
+		id:     fmt.Sprintf("%d", c.count),
+		task:   task,
+		stdout: stdout,
+		stderr: stderr,
+		done:   make(chan result, 1),
+	}
+	c.pending = append(c.pending, a)
+	c.notify()
+	c.lock.Unlock()
+
+	ticker := time.NewTicker(c.timeout / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case r := <-a.done:
+			if outputs, ok := status.OutputsFromContext(ctx); ok {
+				for key, value := range r.Outputs {
+					outputs.Set(task, key, value)
+				}
+			}
+			if r.Error != "" {
+				return r.Status, status.NewError(r.Error, r.ExitCode)
+			}
+			return r.Status, nil
+		case <-ctx.Done():
+			c.lock.Lock()
+			defer c.lock.Unlock()
+
+			a.cancelled = true
+			c.unqueue(a)
+			return status.Failed, nil
+		case now := <-ticker.C:
+			c.expire(now)
+		}
+	}
+}
+
+func (c *Coordinator) register(w http.ResponseWriter, r *http.Request) {
+	var request registration
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not decode registration: %s", err), http.StatusBadRequest)
+		return
+	}
+	if request.Capacity < 1 {
+		http.Error(w, "capacity must be at least 1", http.StatusBadRequest)
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	c.count++
+	id := fmt.Sprintf("%s-%d", request.Name, c.count)
+	c.workers[id] = &worker{registration: request, lastSeen: time.Now()}
+	c.notify()
+
+	respond(w, registered{Worker: id})
+}
+
+func (c *Coordinator) work(w http.ResponseWriter, r *http.Request) {
+	id := r.FormValue("worker")
+	timeout := time.After(c.poll)
+
+	for {
+		c.lock.Lock()
+		worker, ok := c.workers[id]
+		if !ok {
+			c.lock.Unlock()
+			http.Error(w, fmt.Sprintf("worker '%s' is not registered", id), http.StatusNotFound)
+			return
+		}
+
+		worker.lastSeen = time.Now()
+		if a := c.next(worker); a != nil {
+			a.worker = id
+			worker.running++
+			c.assignments[a.id] = a
+			c.lock.Unlock()
+
+			respond(w, work{Assignment: a.id, TaskID: a.task.ID()})
+			return
+		}
+		changed := c.changed
+		c.lock.Unlock()
+
+		select {
+		case <-changed:
+		case <-timeout:
+			w.WriteHeader(http.StatusNoContent)
+			return
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func (c *Coordinator) output(w http.ResponseWriter, r *http.Request) {
+	c.lock.Lock()
+	a, ok := c.assignments[r.FormValue("assignment")]
+	c.lock.Unlock()
+
+	if !ok {
+		http.Error(w, "unknown assignment", http.StatusNotFound)
+		return
+	}
+	if c.isCancelled(a) {
+		http.Error(w, "assignment was cancelled", http.StatusGone)
+		return
+	}
+
+	writer := a.stdout
+	if r.FormValue("stream") == "stderr" {
+		writer = a.stderr
+	}
+	_, err := io.Copy(writer, r.Body)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not write output: %s", err), http.StatusInternalServerError)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Coordinator) result(w http.ResponseWriter, r *http.Request) {
+	var request result
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not decode result: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	a, ok := c.assignments[request.Assignment]
+	if !ok {
+		http.Error(w, "unknown assignment", http.StatusNotFound)
+		return
+	}
+	delete(c.assignments, a.id)
+	if worker, ok := c.workers[a.worker]; ok {
+		worker.running--
+	}
+	c.notify()
+
+	a.done <- request
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c *Coordinator) heartbeat(w http.ResponseWriter, r *http.Request) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	id := r.FormValue("worker")
+	worker, ok := c.workers[id]
+	if !ok {
+		http.Error(w, fmt.Sprintf("worker '%s' is not registered", id), http.StatusNotFound)
+		return
+	}
+	worker.lastSeen = time.Now()
+
+	response := heartbeat{Cancelled: []string{}}
+	for _, a := range c.assignments {
+		if a.worker == id && a.cancelled {
+			response.Cancelled = append(response.Cancelled, a.id)
+		}
+	}
+	respond(w, response)
+}
+
+func (c *Coordinator) expire(now time.Time) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for id, worker := range c.workers {
+		if now.Sub(worker.lastSeen) < c.timeout {
+			continue
+		}
+
+		log.Printf("worker %s stopped heartbeating, failing its tasks", id)
+		delete(c.workers, id)
+		for _, a := range c.assignments {
+			if a.worker != id {
+				continue
+			}
+			delete(c.assignments, a.id)
+			a.done <- result{
+				Assignment: a.id,
+				Status:     status.Errored,
+				Error:      fmt.Sprintf("worker %s stopped heartbeating", id),
+			}
+		}
+	}
+}
+
+func (c *Coordinator) next(worker *worker) *assignment {
+	if worker.running >= worker.Capacity {
+		return nil
+	}
+	for i, a := range c.pending {
+		if matches(worker.Tags, tags(a.task)) {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			return a
+		}
+	}
+	return nil
+}
+
+func (c *Coordinator) unqueue(a *assignment) {
+	for i, other := range c.pending {
+		if other == a {
+			c.pending = append(c.pending[:i], c.pending[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *Coordinator) isCancelled(a *assignment) bool {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	return a.cancelled
+}
+
+func (c *Coordinator) notify() {
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+func tags(task executor.Tasker) []string {
+	for _, unit := range planner.Unwrap(task) {
+		if unit, ok := unit.(executor.TaggedTasker); ok {
+			return unit.Tags()
+		}
+	}
+	return nil
+}
+
+func matches(offered []string, required []string) bool {
+	for _, tag := range required {
+		found := false
+		for _, other := range offered {
+			if tag == other {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func respond(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -0,0 +1,226 @@
+package remote_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/executor/remote"
+	"github.com/jtarchie/dothings/executor/writers"
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	"github.com/jtarchie/dothings/tasks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Coordinator", func() {
+	var (
+		coordinator *remote.Coordinator
+		server      *httptest.Server
+		ctx         context.Context
+		cancel      context.CancelFunc
+		inMemory    executor.Writer
+		statuses    status.Stater
+		stopped     chan error
+		workers     int
+	)
+
+	BeforeEach(func() {
+		coordinator = remote.NewCoordinatorWithPoll(100 * time.Millisecond)
+		server = httptest.NewServer(coordinator)
+		ctx, cancel = context.WithCancel(context.Background())
+		inMemory = writers.NewInMemory()
+		statuses = status.NewStatuses()
+		stopped = make(chan error, 10)
+		workers = 0
+	})
+
+	AfterEach(func() {
+		cancel()
+		for ; workers > 0; workers-- {
+			Expect(<-stopped).NotTo(HaveOccurred())
+		}
+		server.Close()
+	})
+
+	start := func(worker *remote.Worker) {
+		workers++
+		go func() {
+			stopped <- worker.Run(ctx)
+		}()
+	}
+
+	run := func(plan planner.Step) status.Type {
+		return executor.NewExecutorWithStater(plan, inMemory, statuses, executor.WithDispatcher(coordinator)).Wait()
+	}
+
+	It("runs tasks on the registered workers and streams their output", func() {
+		all := []task{{id: "A"}, {id: "B"}, {id: "C"}}
+		recorder := newRecorder()
+		start(remote.NewWorker(server.URL, "one", nil, 1, resolver("one", recorder, all...)))
+		start(remote.NewWorker(server.URL, "two", nil, 1, resolver("two", recorder, all...)))
+
+		plan, _ := planner.NewParallel(func(plan planner.Planner) error {
+			for _, t := range all {
+				plan.Task(t)
+			}
+			return nil
+		})
+
+		Expect(run(plan)).To(Equal(status.Success))
+		for _, t := range all {
+			worker := recorder.workerFor(t.id)
+			Expect(worker).To(BeElementOf("one", "two"))
+
+			stdout, _ := inMemory.GetString(t)
+			Expect(stdout).To(Equal(t.id + " ran on " + worker + "\n"))
+		}
+	})
+
+	It("runs generated tasks locally since workers cannot resolve them", func() {
+		recorder := newRecorder()
+		start(remote.NewWorker(server.URL, "one", nil, 1, resolver("one", recorder, task{id: "A"})))
+
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(task{id: "A"})
+			plan.Generate(generator{id: "discover", generated: "B"})
+			return nil
+		})
+
+		Expect(run(plan)).To(Equal(status.Success))
+		Expect(recorder.workerFor("A")).To(Equal("one"))
+		stdout, _ := inMemory.GetString(tasks.NewEcho("B", status.Success))
+		Expect(stdout).To(ContainSubstring("B"))
+	})
+
+	It("records the outputs set by tasks on a worker", func() {
+		loaded := outputTask{id: "load", key: "version", value: "1.2.3"}
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(loaded)
+			return nil
+		})
+		start(remote.NewWorker(server.URL, "one", nil, 1, remote.NewPlanResolver(plan)))
+
+		outputs := status.NewOutputs()
+		execution := executor.NewExecutorWithOutputs(plan, inMemory, statuses, outputs, executor.WithDispatcher(coordinator))
+		Expect(execution.Wait()).To(Equal(status.Success))
+
+		value, ok := outputs.Get("load", "version")
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal("1.2.3"))
+	})
+
+	It("routes tagged tasks to workers offering every tag", func() {
+		all := []task{
+			{id: "linux", tags: []string{"linux"}},
+			{id: "gpu", tags: []string{"linux", "gpu"}},
+			{id: "any"},
+		}
+		recorder := newRecorder()
+		start(remote.NewWorker(server.URL, "linux", []string{"linux"}, 2, resolver("linux", recorder, all...)))
+		start(remote.NewWorker(server.URL, "gpu", []string{"linux", "gpu"}, 2, resolver("gpu", recorder, all...)))
+
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			for _, t := range all {
+				plan.Task(t)
+			}
+			return nil
+		})
+
+		Expect(run(plan)).To(Equal(status.Success))
+		Expect(recorder.workerFor("linux")).To(BeElementOf("linux", "gpu"))
+		Expect(recorder.workerFor("gpu")).To(Equal("gpu"))
+		Expect(recorder.workerFor("any")).To(BeElementOf("linux", "gpu"))
+	})
+
+	It("routes tagged tasks wrapped by a matrix", func() {
+		ids := []task{{id: "gpu [os=linux]"}, {id: "gpu [os=windows]"}}
+		recorder := newRecorder()
+		start(remote.NewWorker(server.URL, "cpu", nil, 2, resolver("cpu", recorder, ids...)))
+		start(remote.NewWorker(server.URL, "gpu", []string{"gpu"}, 2, resolver("gpu", recorder, ids...)))
+
+		plan, _ := planner.NewMatrix(map[string][]string{"os": {"linux", "windows"}}, func(plan planner.Planner, _ map[string]string) error {
+			plan.Task(task{id: "gpu", tags: []string{"gpu"}})
+			return nil
+		})
+
+		Expect(run(plan)).To(Equal(status.Success))
+		Expect(recorder.workerFor("gpu [os=linux]")).To(Equal("gpu"))
+		Expect(recorder.workerFor("gpu [os=windows]")).To(Equal("gpu"))
+	})
+
+	It("does not assign a worker more tasks than its capacity", func() {
+		all := []task{}
+		for _, id := range []string{"A", "B", "C", "D"} {
+			all = append(all, task{id: id, duration: 100 * time.Millisecond})
+		}
+		recorder := newRecorder()
+		start(remote.NewWorker(server.URL, "one", nil, 2, resolver("one", recorder, all...)))
+
+		plan, _ := planner.NewParallel(func(plan planner.Planner) error {
+			for _, t := range all {
+				plan.Task(t)
+			}
+			return nil
+		})
+
+		Expect(run(plan)).To(Equal(status.Success))
+		Expect(recorder.concurrency()).To(Equal(2))
+	})
+
+	It("reports failures and exit codes from the worker", func() {
+		command := tasks.NewCommand("bash", "-c", "echo from the worker; exit 3")
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(command)
+			return nil
+		})
+		start(remote.NewWorker(server.URL, "one", nil, 1, remote.NewPlanResolver(plan)))
+
+		Expect(run(plan)).To(Equal(status.Failed))
+
+		stdout, _ := inMemory.GetString(command)
+		Expect(stdout).To(Equal("from the worker\n"))
+		attempts := statuses.Attempts(command)
+		Expect(attempts[0].Error).To(Equal("exit status 3"))
+		Expect(*attempts[0].ExitCode).To(Equal(3))
+	})
+
+	It("errors tasks the worker cannot find", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(task{id: "A"})
+			return nil
+		})
+		start(remote.NewWorker(server.URL, "one", nil, 1, resolver("one", newRecorder())))
+
+		Expect(run(plan)).To(Equal(status.Errored))
+		Expect(statuses.Attempts(task{id: "A"})[0].Error).To(Equal("worker one could not find task 'A'"))
+	})
+
+	It("cancels tasks running on a worker", func() {
+		slow := tasks.NewCommand("bash", "-c", "while true; do echo waiting; sleep 0.1; done")
+		plan, _ := planner.NewParallel(func(plan planner.Planner) error {
+			plan.Task(slow)
+			plan.Task(tasks.NewCommand("bash", "-c", "sleep 0.5; exit 1"))
+			return nil
+		}, planner.WithFailFast())
+		start(remote.NewWorker(server.URL, "one", nil, 2, remote.NewPlanResolver(plan)))
+
+		startTime := time.Now()
+		Expect(run(plan)).To(Equal(status.Failed))
+		Expect(time.Since(startTime)).To(BeNumerically("<", 5*time.Second))
+		Eventually(func() []status.Type {
+			return statuses.Get(slow)
+		}).Should(Equal([]status.Type{status.Failed}))
+
+		stdout, _ := inMemory.GetString(slow)
+		Expect(strings.Count(stdout, "waiting")).To(BeNumerically(">", 1))
+	})
+
+	It("rejects workers without capacity", func() {
+		worker := remote.NewWorker(server.URL, "none", nil, 0, resolver("none", newRecorder()))
+		Expect(worker.Run(ctx)).To(MatchError("could not register worker: capacity must be at least 1"))
+	})
+})
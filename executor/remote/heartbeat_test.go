@@ -0,0 +1,150 @@
+package remote_test
+
+import (
+	"context"
+	"io"
+	"net/http/httptest"
+	"time"
+
+	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/executor/remote"
+	"github.com/jtarchie/dothings/executor/writers"
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	"github.com/jtarchie/dothings/tasks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Heartbeats", func() {
+	var (
+		coordinator *remote.Coordinator
+		server      *httptest.Server
+		inMemory    executor.Writer
+		statuses    status.Stater
+		cancels     []context.CancelFunc
+	)
+
+	BeforeEach(func() {
+		coordinator = remote.NewCoordinatorWithTimeout(50*time.Millisecond, 300*time.Millisecond)
+		server = httptest.NewServer(coordinator)
+		inMemory = writers.NewInMemory()
+		statuses = status.NewStatuses()
+		cancels = nil
+	})
+
+	AfterEach(func() {
+		for _, cancel := range cancels {
+			cancel()
+		}
+		server.Close()
+	})
+
+	start := func(worker *remote.Worker) context.CancelFunc {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancels = append(cancels, cancel)
+		go func() {
+			_ = worker.Run(ctx)
+		}()
+		return cancel
+	}
+
+	run := func(plan planner.Step) status.Type {
+		return executor.NewExecutorWithStater(plan, inMemory, statuses, executor.WithDispatcher(coordinator)).Wait()
+	}
+
+	resolveTo := func(task executor.Tasker) remote.Resolver {
+		return func(string) (executor.Tasker, bool) {
+			return task, true
+		}
+	}
+
+	hanging := tasks.NewCommand("bash", "-c", "echo started; sleep 10")
+
+	It("errors tasks running on a worker that stopped heartbeating", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(task{id: "A"})
+			return nil
+		})
+		stop := start(remote.NewWorkerWithHeartbeat(server.URL, "flaky", nil, 1, resolveTo(hanging), 50*time.Millisecond))
+
+		go func() {
+			defer GinkgoRecover()
+			Eventually(func() string {
+				stdout, _ := inMemory.GetString(task{id: "A"})
+				return stdout
+			}).Should(ContainSubstring("started"))
+			stop()
+		}()
+
+		Expect(run(plan)).To(Equal(status.Errored))
+		Expect(statuses.Attempts(task{id: "A"})[0].Error).To(MatchRegexp(`worker flaky-\d+ stopped heartbeating`))
+	})
+
+	It("retries tasks from a dead worker according to the attempts", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(task{id: "A"})
+			return nil
+		}, planner.WithAttempts(2))
+		stop := start(remote.NewWorkerWithHeartbeat(server.URL, "flaky", nil, 1, resolveTo(hanging), 50*time.Millisecond))
+
+		recorder := newRecorder()
+		go func() {
+			defer GinkgoRecover()
+			Eventually(func() string {
+				stdout, _ := inMemory.GetString(task{id: "A"})
+				return stdout
+			}).Should(ContainSubstring("started"))
+			stop()
+			start(remote.NewWorkerWithHeartbeat(server.URL, "healthy", nil, 1, resolver("healthy", recorder, task{id: "A"}), 50*time.Millisecond))
+		}()
+
+		Expect(run(plan)).To(Equal(status.Success))
+		Expect(statuses.Get(task{id: "A"})).To(Equal([]status.Type{status.Errored, status.Success}))
+		Expect(recorder.workerFor("A")).To(Equal("healthy"))
+	})
+
+	It("keeps busy workers alive while they heartbeat", func() {
+		slow := task{id: "A", duration: time.Second}
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(slow)
+			return nil
+		})
+		start(remote.NewWorkerWithHeartbeat(server.URL, "busy", nil, 1, resolver("busy", newRecorder(), slow), 50*time.Millisecond))
+
+		Expect(run(plan)).To(Equal(status.Success))
+	})
+
+	It("cancels silent tasks through the heartbeat", func() {
+		silent := waitingTask{id: "silent", cancelled: make(chan struct{})}
+		failing := tasks.NewCommand("bash", "-c", "sleep 0.3; exit 1")
+		plan, _ := planner.NewParallel(func(plan planner.Planner) error {
+			plan.Task(silent)
+			plan.Task(failing)
+			return nil
+		}, planner.WithFailFast())
+		start(remote.NewWorkerWithHeartbeat(server.URL, "one", nil, 2, remote.NewPlanResolver(plan), 50*time.Millisecond))
+
+		Expect(run(plan)).To(Equal(status.Failed))
+		Eventually(silent.cancelled).Should(BeClosed())
+	})
+})
+
+type waitingTask struct {
+	id        string
+	cancelled chan struct{}
+}
+
+func (w waitingTask) ID() string {
+	return w.id
+}
+
+func (w waitingTask) Execute(stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	return w.ExecuteContext(context.Background(), stdout, stderr)
+}
+
+func (w waitingTask) ExecuteContext(ctx context.Context, _ io.Writer, _ io.Writer) (status.Type, error) {
+	<-ctx.Done()
+	close(w.cancelled)
+	return status.Failed, nil
+}
@@ -0,0 +1,148 @@
+package remote_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	"github.com/jtarchie/dothings/tasks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func TestRemote(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Remote Suite")
+}
+
+type recorder struct {
+	sync.Mutex
+	ran     map[string]string
+	running int
+	most    int
+}
+
+func newRecorder() *recorder {
+	return &recorder{ran: map[string]string{}}
+}
+
+func (r *recorder) started(worker, id string) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.ran[id] = worker
+	r.running++
+	if r.running > r.most {
+		r.most = r.running
+	}
+}
+
+func (r *recorder) finished() {
+	r.Lock()
+	defer r.Unlock()
+
+	r.running--
+}
+
+func (r *recorder) workerFor(id string) string {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.ran[id]
+}
+
+func (r *recorder) concurrency() int {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.most
+}
+
+type task struct {
+	id       string
+	tags     []string
+	duration time.Duration
+}
+
+func (t task) ID() string {
+	return t.id
+}
+
+func (t task) Tags() []string {
+	return t.tags
+}
+
+func (t task) Execute(io.Writer, io.Writer) (status.Type, error) {
+	return status.Errored, fmt.Errorf("task %s should only run on a worker", t.id)
+}
+
+type workerTask struct {
+	task
+	worker   string
+	recorder *recorder
+}
+
+func (w workerTask) Execute(stdout io.Writer, _ io.Writer) (status.Type, error) {
+	w.recorder.started(w.worker, w.id)
+	defer w.recorder.finished()
+
+	_, _ = fmt.Fprintf(stdout, "%s ran on %s\n", w.id, w.worker)
+	time.Sleep(w.duration)
+	return status.Success, nil
+}
+
+type outputTask struct {
+	id    string
+	key   string
+	value string
+}
+
+func (o outputTask) ID() string {
+	return o.id
+}
+
+func (o outputTask) Execute(io.Writer, io.Writer) (status.Type, error) {
+	return status.Errored, fmt.Errorf("task %s needs outputs to run", o.id)
+}
+
+func (o outputTask) ExecuteContext(ctx context.Context, _ io.Writer, _ io.Writer) (status.Type, error) {
+	outputs, ok := status.OutputsFromContext(ctx)
+	if !ok {
+		return status.Errored, fmt.Errorf("task %s needs outputs to run", o.id)
+	}
+	outputs.Set(o, o.key, o.value)
+	return status.Success, nil
+}
+
+type generator struct {
+	id        string
+	generated string
+}
+
+func (g generator) ID() string {
+	return g.id
+}
+
+func (g generator) Generate(io.Writer, io.Writer) (func(planner.Planner) error, error) {
+	return func(plan planner.Planner) error {
+		plan.Task(tasks.NewEcho(g.generated, status.Success))
+		return nil
+	}, nil
+}
+
+func resolver(worker string, recorder *recorder, tasks ...task) func(string) (executor.Tasker, bool) {
+	return func(id string) (executor.Tasker, bool) {
+		for _, t := range tasks {
+			if t.id == id {
+				return workerTask{task: t, worker: worker, recorder: recorder}, true
+			}
+		}
+		return nil, false
+	}
+}
@@ -0,0 +1,289 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+type Resolver func(id string) (executor.Tasker, bool)
+
+func NewPlanResolver(plan planner.Step) Resolver {
+	return func(id string) (executor.Tasker, bool) {
+		node, ok := plan.Tree().Find(func(node planner.Tree) bool {
+			return node.Type() == planner.Task && node.Task().ID() == id
+		})
+		if !ok {
+			return nil, false
+		}
+		return node.Task(), true
+	}
+}
+
+type Worker struct {
+	url       string
+	name      string
+	tags      []string
+	capacity  int
+	resolve   Resolver
+	heartbeat time.Duration
+	http      *http.Client
+
+	lock    sync.Mutex
+	running map[string]context.CancelFunc
+}
+
+func NewWorker(
+	url string,
+	name string,
+	tags []string,
+	capacity int,
+	resolve Resolver,
+) *Worker {
+	return NewWorkerWithHeartbeat(url, name, tags, capacity, resolve, 5*time.Second)
+}
+
+func NewWorkerWithHeartbeat(
+	url string,
+	name string,
+	tags []string,
+	capacity int,
+	resolve Resolver,
+	heartbeat time.Duration,
+) *Worker {
+	return &Worker{
+		url:       strings.TrimSuffix(url, "/"),
+		name:      name,
+		tags:      tags,
+		capacity:  capacity,
+		resolve:   resolve,
+		heartbeat: heartbeat,
+		http:      &http.Client{},
+		running:   map[string]context.CancelFunc{},
+	}
+}
+
+func (w *Worker) Run(ctx context.Context) error {
+	var response registered
+	err := w.post(ctx, "/register", registration{
+		Name:     w.name,
+		Tags:     w.tags,
+		Capacity: w.capacity,
+	}, &response)
+	if err != nil {
+		return fmt.Errorf("could not register worker: %s", err)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	errs := make(chan error, w.capacity+1)
+	wg := &sync.WaitGroup{}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		errs <- w.beat(ctx, response.Worker)
+	}()
+	for i := 0; i < w.capacity; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- w.loop(ctx, response.Worker)
+		}()
+	}
+
+	err = <-errs
+	cancel()
+	wg.Wait()
+	return err
+}
+
+func (w *Worker) loop(ctx context.Context, id string) error {
+	for {
+		var assigned work
+		err := w.post(ctx, "/work?worker="+id, nil, &assigned)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not get work: %s", err)
+		}
+		if assigned.Assignment == "" {
+			continue
+		}
+
+		err = w.post(ctx, "/result", w.run(ctx, assigned), nil)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not report result: %s", err)
+		}
+	}
+}
+
+func (w *Worker) beat(ctx context.Context, id string) error {
+	ticker := time.NewTicker(w.heartbeat)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		var response heartbeat
+		err := w.post(ctx, "/heartbeat?worker="+id, nil, &response)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if e, ok := err.(*statusError); ok && e.code == http.StatusNotFound {
+			return fmt.Errorf("worker %s is no longer registered: %s", id, err)
+		}
+		if err != nil {
+			log.Printf("could not send heartbeat: %s", err)
+			continue
+		}
+
+		for _, assignment := range response.Cancelled {
+			w.cancel(assignment)
+		}
+	}
+}
+
+func (w *Worker) track(assignment string, cancel context.CancelFunc) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	w.running[assignment] = cancel
+}
+
+func (w *Worker) untrack(assignment string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	delete(w.running, assignment)
+}
+
+func (w *Worker) cancel(assignment string) {
+	w.lock.Lock()
+	defer w.lock.Unlock()
+
+	if cancel, ok := w.running[assignment]; ok {
+		cancel()
+	}
+}
+
+func (w *Worker) run(ctx context.Context, assigned work) result {
+	task, ok := w.resolve(assigned.TaskID)
+	if !ok {
+		return result{
+			Assignment: assigned.Assignment,
+			Status:     status.Errored,
+			Error:      fmt.Sprintf("worker %s could not find task '%s'", w.name, assigned.TaskID),
+		}
+	}
+
+	outputs := status.NewOutputs()
+	ctx, cancel := context.WithCancel(status.WithOutputs(ctx, outputs))
+	defer cancel()
+	w.track(assigned.Assignment, cancel)
+	defer w.untrack(assigned.Assignment)
+
+	stdout := &stream{worker: w, ctx: ctx, cancel: cancel, assignment: assigned.Assignment, name: "stdout"}
+	stderr := &stream{worker: w, ctx: ctx, cancel: cancel, assignment: assigned.Assignment, name: "stderr"}
+
+	finalState, err := executor.Execute(ctx, task, stdout, stderr)
+	report := result{
+		Assignment: assigned.Assignment,
+		Status:     finalState,
+		Outputs:    outputs.Values(task.ID()),
+	}
+	if err != nil {
+		log.Printf("task %s failed execution: %s", task.ID(), err)
+		report.Error = err.Error()
+		if code, ok := status.ExitCode(err); ok {
+			report.ExitCode = &code
+		}
+	}
+	return report
+}
+
+func (w *Worker) post(ctx context.Context, path string, value interface{}, response interface{}) error {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("could not encode request: %s", err)
+	}
+	return w.send(ctx, path, body, response)
+}
+
+func (w *Worker) send(ctx context.Context, path string, body []byte, response interface{}) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.http.Do(request)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		if response == nil {
+			return nil
+		}
+		return json.NewDecoder(resp.Body).Decode(response)
+	case http.StatusNoContent:
+		return nil
+	}
+
+	message, _ := ioutil.ReadAll(resp.Body)
+	return &statusError{code: resp.StatusCode, message: strings.TrimSpace(string(message))}
+}
+
+type statusError struct {
+	code    int
+	message string
+}
+
+func (s *statusError) Error() string {
+	return s.message
+}
+
+type stream struct {
+	worker     *Worker
+	ctx        context.Context
+	cancel     context.CancelFunc
+	assignment string
+	name       string
+}
+
+func (s *stream) Write(p []byte) (int, error) {
+	err := s.worker.send(
+		s.ctx,
+		fmt.Sprintf("/output?assignment=%s&stream=%s", s.assignment, s.name),
+		p,
+		nil,
+	)
+	if e, ok := err.(*statusError); ok && e.code == http.StatusGone {
+		s.cancel()
+	}
+	if err != nil {
+		return 0, fmt.Errorf("could not stream %s: %s", s.name, err)
+	}
+	return len(p), nil
+}
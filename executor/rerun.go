@@ -0,0 +1,14 @@
+package executor
+
+import (
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+func Rerun(plan planner.Step, stater status.Stater) status.Stater {
+	ids := []string{}
+	for _, task := range planner.Rerun(plan, stater) {
+		ids = append(ids, task.ID())
+	}
+	return status.NewRerun(stater, ids)
+}
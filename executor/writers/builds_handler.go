@@ -0,0 +1,92 @@
+package writers
+
+import (
+	"fmt"
+	"html"
+	"net/http"
+	"strings"
+
+	"github.com/jtarchie/dothings/executor"
+)
+
+type buildsHandler struct {
+	manager *executor.Manager
+}
+
+func NewBuildsHandler(manager *executor.Manager) *buildsHandler {
+	return &buildsHandler{
+		manager: manager,
+	}
+}
+
+func (h *buildsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := strings.Trim(strings.TrimPrefix(r.URL.Path, "/builds"), "/")
+	if path == "" {
+		h.list(w)
+		return
+	}
+
+	parts := strings.SplitN(path, "/", 2)
+	build, ok := h.manager.Get(parts[0])
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	switch {
+	case len(parts) == 1:
+		NewWebHandlerWithApprover(build.Plan, build.Writer, build.Stater, build).ServeHTTP(w, r)
+	case parts[1] == "abort" && r.Method == http.MethodPost:
+		err := h.manager.Abort(build.ID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("could not abort build: %s", err), http.StatusBadRequest)
+			return
+		}
+		http.Redirect(w, r, "/builds/"+build.ID, http.StatusSeeOther)
+	case parts[1] == "rerun" && r.Method == http.MethodPost:
+		rerun, err := h.manager.Rerun(build.ID, NewInMemory())
+		if err != nil {
+			http.Error(w, fmt.Sprintf("could not rerun build: %s", err), http.StatusBadRequest)
+			return
+		}
+		http.Redirect(w, r, "/builds/"+rerun.ID, http.StatusSeeOther)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *buildsHandler) list(w http.ResponseWriter) {
+	_, _ = fmt.Fprint(w, `<html>
+	<head>
+		<meta charset="utf-8">
+		<link rel="stylesheet" href="https://unpkg.com/picnic">
+		<meta name="viewport" content="width=device-width, initial-scale=1">
+		<style>
+			.container { padding: 20px }
+		</style>
+	</head>
+	<body>
+	<div class="container"><table><thead><tr><th>build</th><th>status</th><th></th></tr></thead><tbody>`)
+	for _, build := range h.manager.List() {
+		state := build.State().String()
+		if build.Aborted() {
+			state += " (aborted)"
+		}
+		abort := ""
+		if !build.Finished() {
+			abort = fmt.Sprintf(`<form method="post" action="/builds/%s/abort"><button class="error">Abort</button></form>`, build.ID)
+		}
+		_, _ = fmt.Fprintf(
+			w,
+			`<tr class="build"><td><a href="/builds/%s">%s</a></td><td class="status">%s</td><td>%s<form method="post" action="/builds/%s/rerun"><button>Rerun failed</button></form></td></tr>`,
+			build.ID,
+			html.EscapeString(build.ID),
+			state,
+			abort,
+			build.ID,
+		)
+	}
+	_, _ = fmt.Fprint(w, `</tbody></table></div>
+	</body></html>
+	`)
+}
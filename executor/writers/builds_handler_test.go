@@ -0,0 +1,109 @@
+package writers_test
+
+import (
+	"io/ioutil"
+	"net/http/httptest"
+
+	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/executor/writers"
+	dothings "github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	"github.com/jtarchie/dothings/tasks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BuildsHandler", func() {
+	var manager *executor.Manager
+
+	request := func(method, path string) (int, string) {
+		w := httptest.NewRecorder()
+		writers.NewBuildsHandler(manager).ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		body, err := ioutil.ReadAll(w.Result().Body)
+		Expect(err).NotTo(HaveOccurred())
+		return w.Code, string(body)
+	}
+
+	submit := func(name string, s status.Type) *executor.Build {
+		plan, _ := dothings.NewSerial(func(plan dothings.Planner) error {
+			plan.Task(tasks.NewEcho(name, s))
+			return nil
+		})
+		return manager.Submit(plan, writers.NewInMemory(), status.NewStatuses())
+	}
+
+	BeforeEach(func() {
+		manager = executor.NewManager(0)
+	})
+
+	It("lists every build", func() {
+		Expect(submit("task 1", status.Success).Wait()).To(Equal(status.Success))
+		Expect(submit("task 2", status.Failed).Wait()).To(Equal(status.Failed))
+
+		code, body := request("GET", "/builds")
+		Expect(code).To(Equal(200))
+		Expect(body).To(ContainSubstring(`<td><a href="/builds/1">1</a></td><td class="status">success</td>`))
+		Expect(body).To(ContainSubstring(`<td><a href="/builds/2">2</a></td><td class="status">failed</td>`))
+	})
+
+	It("routes to the page of a single build", func() {
+		submit("task 1", status.Success).Wait()
+		submit("task 2", status.Failed).Wait()
+
+		code, body := request("GET", "/builds/2")
+		Expect(code).To(Equal(200))
+		Expect(body).To(ContainSubstring(`<div class="container failed">`))
+		Expect(body).To(ContainSubstring(`<header class="id">task 2</header>`))
+		Expect(body).NotTo(ContainSubstring(`task 1`))
+
+		code, _ = request("GET", "/builds/3")
+		Expect(code).To(Equal(404))
+	})
+
+	It("aborts a build", func() {
+		manager = executor.NewManager(1)
+		plan, _ := dothings.NewSerial(func(plan dothings.Planner) error {
+			plan.Task(tasks.NewCommand("sleep", "10"))
+			return nil
+		})
+		running := manager.Submit(plan, writers.NewInMemory(), status.NewStatuses())
+		queued := submit("task 2", status.Success)
+
+		_, body := request("GET", "/builds")
+		Expect(body).To(ContainSubstring(`<form method="post" action="/builds/1/abort">`))
+		Expect(body).To(ContainSubstring(`<form method="post" action="/builds/2/abort">`))
+
+		code, _ := request("POST", "/builds/2/abort")
+		Expect(code).To(Equal(303))
+		Expect(queued.Wait()).To(Equal(status.Failed))
+
+		code, _ = request("POST", "/builds/1/abort")
+		Expect(code).To(Equal(303))
+		Expect(running.Wait()).To(Equal(status.Failed))
+
+		code, body = request("POST", "/builds/1/abort")
+		Expect(code).To(Equal(400))
+		Expect(body).To(ContainSubstring("could not abort build: build '1' has already finished"))
+
+		_, body = request("GET", "/builds")
+		Expect(body).To(ContainSubstring(`<td class="status">failed (aborted)</td>`))
+		Expect(body).NotTo(ContainSubstring(`/abort"`))
+	})
+
+	It("reruns a finished build", func() {
+		Expect(submit("task 1", status.Failed).Wait()).To(Equal(status.Failed))
+
+		code, _ := request("POST", "/builds/1/rerun")
+		Expect(code).To(Equal(303))
+
+		rerun, ok := manager.Get("2")
+		Expect(ok).To(BeTrue())
+		Expect(rerun.Wait()).To(Equal(status.Failed))
+
+		code, _ = request("POST", "/builds/3/rerun")
+		Expect(code).To(Equal(404))
+
+		_, body := request("GET", "/builds")
+		Expect(body).To(ContainSubstring(`<form method="post" action="/builds/1/rerun"><button>Rerun failed</button></form>`))
+	})
+})
@@ -2,8 +2,10 @@ package writers
 
 import (
 	"fmt"
+	"html"
 	"io"
 	"net/http"
+	"time"
 
 	"github.com/jtarchie/dothings/status"
 
@@ -12,25 +14,58 @@ import (
 	"github.com/jtarchie/dothings/planner"
 )
 
+type Approver interface {
+	Approve(id string) error
+	Reject(id string) error
+}
+
 type handler struct {
-	plan   planner.Step
-	writer executor.Writer
-	stater status.Stater
+	plan     planner.Step
+	writer   executor.Writer
+	stater   status.Stater
+	approver Approver
+	clock    func() time.Time
 }
 
 func NewWebHandler(
 	plan planner.Step,
 	writer executor.Writer,
 	stater status.Stater,
+) *handler {
+	return NewWebHandlerWithApprover(plan, writer, stater, nil)
+}
+
+func NewWebHandlerWithApprover(
+	plan planner.Step,
+	writer executor.Writer,
+	stater status.Stater,
+	approver Approver,
+) *handler {
+	return NewWebHandlerWithClock(plan, writer, stater, approver, time.Now)
+}
+
+func NewWebHandlerWithClock(
+	plan planner.Step,
+	writer executor.Writer,
+	stater status.Stater,
+	approver Approver,
+	clock func() time.Time,
 ) *handler {
 	return &handler{
-		plan:   plan,
-		writer: writer,
-		stater: stater,
+		plan:     plan,
+		writer:   writer,
+		stater:   stater,
+		approver: approver,
+		clock:    clock,
 	}
 }
 
 func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method == http.MethodPost {
+		h.decide(w, r)
+		return
+	}
+
 	currentStatus := h.plan.State(h.stater)
 	_, _ = fmt.Fprintf(w, `<html>
 	<head>
@@ -51,6 +86,17 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 				padding: 5px;
 				background-color: rgba(1,255,112, 0.2);
 			}
+			.type-graph {
+				padding: 5px;
+				background-color: rgba(177,13,201,0.2);
+			}
+			.type-generated {
+				padding: 5px;
+				background-color: rgba(255,220,0,0.2);
+			}
+			.type-conditional.skipped {
+				opacity: 0.5;
+			}
 			.type-task {
 				background-color: #fff;
 			}
@@ -78,6 +124,24 @@ func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			.status.failed .id:before {
 				background-color: #ff4136;
 			}
+			.status.skipped .id:before {
+				background-color: #ddd;
+			}
+			.status.pending .id:before {
+				background-color: #b10dc9;
+			}
+			.duration {
+				padding: 0 10px;
+				color: #888;
+				font-size: 0.8em;
+			}
+			.error {
+				padding: 0 10px;
+				color: #ff4136;
+			}
+			.approval {
+				padding: 0 10px 10px;
+			}
 		</style>
 	</head>
 	<body>
@@ -121,15 +185,28 @@ func (h *handler) writeTree(
 ) {
 
 	if tree.Type() == planner.Task {
-		status := h.stater.Get(tree.Task())
-		if len(status) > 0 {
-			_, _ = fmt.Fprintf(writer, `<article class="card type-%s status %s">`, tree.Type(), status[0])
+		states := h.stater.Get(tree.Task())
+		if len(states) > 0 {
+			_, _ = fmt.Fprintf(writer, `<article class="card type-%s status %s">`, tree.Type(), states[0])
 		} else {
 			_, _ = fmt.Fprintf(writer, `<article class="card type-%s status">`, tree.Type())
 		}
 
 		stdout, _ := h.writer.GetString(tree.Task())
 		_, _ = fmt.Fprintf(writer, `<header class="id">%s</header>`, tree.Task().ID())
+		if attempts := h.stater.Attempts(tree.Task()); len(attempts) > 0 {
+			_, _ = fmt.Fprintf(writer, `<div class="duration">%s</div>`, elapsed(attempts, h.clock()))
+			if latest := attempts[len(attempts)-1]; latest.Error != "" {
+				_, _ = fmt.Fprintf(writer, `<div class="error">%s</div>`, html.EscapeString(reason(latest)))
+			}
+		}
+		if h.approver != nil && len(states) > 0 && states[len(states)-1] == status.Pending {
+			_, _ = fmt.Fprintf(
+				writer,
+				`<form class="approval" method="post"><input type="hidden" name="id" value="%s"><button name="decision" value="approve" class="success">Approve</button> <button name="decision" value="reject" class="error">Reject</button></form>`,
+				html.EscapeString(tree.Task().ID()),
+			)
+		}
 		_, _ = fmt.Fprintf(
 			writer,
 			`<div class="term-container">%s</div>`,
@@ -139,6 +216,8 @@ func (h *handler) writeTree(
 				),
 			),
 		)
+	} else if tree.Type() == planner.Conditional {
+		_, _ = fmt.Fprintf(writer, `<div class="type-%s %s">`, tree.Type(), tree.State(h.stater))
 	} else {
 		_, _ = fmt.Fprintf(writer, `<div class="type-%s">`, tree.Type())
 	}
@@ -147,3 +226,47 @@ func (h *handler) writeTree(
 	}
 	_, _ = fmt.Fprint(writer, "</article>")
 }
+
+func (h *handler) decide(w http.ResponseWriter, r *http.Request) {
+	if h.approver == nil {
+		http.Error(w, "approvals are not supported", http.StatusMethodNotAllowed)
+		return
+	}
+
+	id := r.FormValue("id")
+
+	var err error
+	switch r.FormValue("decision") {
+	case "approve":
+		err = h.approver.Approve(id)
+	case "reject":
+		err = h.approver.Reject(id)
+	default:
+		err = fmt.Errorf("unknown decision '%s'", r.FormValue("decision"))
+	}
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not decide approval: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	http.Redirect(w, r, r.URL.Path, http.StatusSeeOther)
+}
+
+func elapsed(attempts []status.Attempt, now time.Time) string {
+	latest := attempts[len(attempts)-1]
+	description := fmt.Sprintf("queued %s", latest.Queued(now).Round(time.Millisecond))
+	if _, ok := latest.StartedAt(); ok {
+		description += fmt.Sprintf(", ran %s", latest.Ran(now).Round(time.Millisecond))
+	}
+	if len(attempts) > 1 {
+		description += fmt.Sprintf(" (attempt %d)", len(attempts))
+	}
+	return description
+}
+
+func reason(attempt status.Attempt) string {
+	if attempt.ExitCode != nil {
+		return fmt.Sprintf("%s (exit code %d)", attempt.Error, *attempt.ExitCode)
+	}
+	return attempt.Error
+}
@@ -1,8 +1,11 @@
 package writers_test
 
 import (
+	"io"
 	"io/ioutil"
+	"net/http"
 	"net/http/httptest"
+	"strings"
 
 	"github.com/jtarchie/dothings/executor"
 	"github.com/jtarchie/dothings/executor/writers"
@@ -52,4 +55,189 @@ var _ = Describe("WebHandler", func() {
 		Expect(body).To(ContainSubstring("out: executing task 2"))
 		Expect(body).To(ContainSubstring("err: executing task 2"))
 	})
+
+	It("shows how long each task was queued and ran", func() {
+		plan, _ := dothings.NewSerial(func(plan dothings.Planner) error {
+			plan.Task(tasks.NewEcho("task 1", status.Success))
+			plan.Task(tasks.NewEcho("task 2", status.Success))
+			return nil
+		})
+
+		inMemory := writers.NewInMemory()
+		statuses := status.NewStatuses()
+		handler := writers.NewWebHandler(plan, inMemory, statuses)
+
+		Expect(statuses.Add(tasks.NewEcho("task 1", status.Success), status.Unstarted)).To(Succeed())
+		Expect(statuses.Add(tasks.NewEcho("task 1", status.Success), status.Running)).To(Succeed())
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		respBody, err := ioutil.ReadAll(w.Result().Body)
+		Expect(err).NotTo(HaveOccurred())
+
+		body := string(respBody)
+		Expect(body).To(MatchRegexp(`<header class="id">task 1</header><div class="duration">queued [\d.]+[mµn]?s, ran [\d.]+[mµn]?s</div>`))
+		Expect(body).To(ContainSubstring(`<header class="id">task 2</header><div class="term-container">`))
+	})
+
+	It("shows why a task did not succeed", func() {
+		command := tasks.NewCommand("bash", "-c", "exit 3")
+		plan, _ := dothings.NewSerial(func(plan dothings.Planner) error {
+			plan.Task(command)
+			return nil
+		})
+
+		inMemory := writers.NewInMemory()
+		statuses := status.NewStatuses()
+		handler := writers.NewWebHandler(plan, inMemory, statuses)
+		Expect(executor.NewExecutorWithStater(plan, inMemory, statuses).Wait()).To(Equal(status.Failed))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		respBody, err := ioutil.ReadAll(w.Result().Body)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(respBody)).To(ContainSubstring(`<div class="error">exit status 3 (exit code 3)</div>`))
+	})
+
+	It("marks conditional steps that did not run as skipped", func() {
+		plan, _ := dothings.NewSerial(func(plan dothings.Planner) error {
+			plan.Task(tasks.NewEcho("task 1", status.Success))
+			return plan.When(func(status.Stater) bool {
+				return false
+			}, func(plan dothings.Planner) error {
+				plan.Task(tasks.NewEcho("task 2", status.Success))
+				return nil
+			})
+		})
+
+		inMemory := writers.NewInMemory()
+		statuses := status.NewStatuses()
+		handler := writers.NewWebHandler(plan, inMemory, statuses)
+
+		executor.NewExecutorWithStater(
+			plan,
+			inMemory,
+			statuses,
+		).Wait()
+
+		req := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		respBody, err := ioutil.ReadAll(w.Result().Body)
+		Expect(err).NotTo(HaveOccurred())
+
+		body := string(respBody)
+		Expect(body).To(ContainSubstring(`<div class="type-conditional skipped">`))
+		Expect(body).To(ContainSubstring(`<header class="id">task 2</header>`))
+	})
+
+	It("renders the steps of a generated plan", func() {
+		plan, _ := dothings.NewSerial(func(plan dothings.Planner) error {
+			plan.Generate(generator{"discover"})
+			return nil
+		})
+
+		inMemory := writers.NewInMemory()
+		statuses := status.NewStatuses()
+		handler := writers.NewWebHandler(plan, inMemory, statuses)
+
+		executor.NewExecutorWithStater(
+			plan,
+			inMemory,
+			statuses,
+		).Wait()
+
+		req := httptest.NewRequest("GET", "/", nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+
+		respBody, err := ioutil.ReadAll(w.Result().Body)
+		Expect(err).NotTo(HaveOccurred())
+
+		body := string(respBody)
+		Expect(body).To(ContainSubstring(`<div class="type-generated">`))
+		Expect(body).To(ContainSubstring(`<header class="id">discover</header>`))
+		Expect(body).To(ContainSubstring(`<header class="id">task 1</header>`))
+		Expect(body).To(ContainSubstring(`<header class="id">task 2</header>`))
+	})
+
+	It("approves a pending task from the page", func() {
+		gate := tasks.NewApproval("prod", 0)
+		plan, _ := dothings.NewSerial(func(plan dothings.Planner) error {
+			plan.Task(gate)
+			plan.Task(tasks.NewEcho("deploy", status.Success))
+			return nil
+		})
+
+		inMemory := writers.NewInMemory()
+		statuses := status.NewStatuses()
+		e := executor.NewExecutorWithStater(plan, inMemory, statuses)
+		handler := writers.NewWebHandlerWithApprover(plan, inMemory, statuses, e)
+
+		done := make(chan status.Type)
+		go func() {
+			done <- e.Wait()
+		}()
+		Eventually(func() []status.Type {
+			return statuses.Get(gate)
+		}, 5).Should(Equal([]status.Type{status.Pending}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		respBody, err := ioutil.ReadAll(w.Result().Body)
+		Expect(err).NotTo(HaveOccurred())
+
+		body := string(respBody)
+		Expect(body).To(ContainSubstring(`<article class="card type-task status pending">`))
+		Expect(body).To(ContainSubstring(`<input type="hidden" name="id" value="approval: prod">`))
+		Expect(body).To(ContainSubstring(`value="approve"`))
+		Expect(body).To(ContainSubstring(`value="reject"`))
+
+		req := httptest.NewRequest("POST", "/", strings.NewReader("id=approval%3A+prod&decision=approve"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w = httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(http.StatusSeeOther))
+
+		Eventually(done, 5).Should(Receive(Equal(status.Success)))
+	})
+
+	It("rejects decisions for tasks that are not pending", func() {
+		plan, _ := dothings.NewSerial(func(plan dothings.Planner) error {
+			plan.Task(tasks.NewEcho("deploy", status.Success))
+			return nil
+		})
+
+		inMemory := writers.NewInMemory()
+		statuses := status.NewStatuses()
+		e := executor.NewExecutorWithStater(plan, inMemory, statuses)
+		handler := writers.NewWebHandlerWithApprover(plan, inMemory, statuses, e)
+
+		req := httptest.NewRequest("POST", "/", strings.NewReader("id=deploy&decision=reject"))
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, req)
+		Expect(w.Code).To(Equal(http.StatusBadRequest))
+		Expect(w.Body.String()).To(ContainSubstring("task 'deploy' is not waiting for approval"))
+	})
 })
+
+type generator struct {
+	id string
+}
+
+func (g generator) ID() string {
+	return g.id
+}
+
+func (g generator) Generate(io.Writer, io.Writer) (func(dothings.Planner) error, error) {
+	return func(plan dothings.Planner) error {
+		return plan.Parallel(func(plan dothings.Planner) error {
+			plan.Task(tasks.NewEcho("task 1", status.Success))
+			plan.Task(tasks.NewEcho("task 2", status.Success))
+			return nil
+		})
+	}, nil
+}
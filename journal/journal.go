@@ -0,0 +1,325 @@
+package journal
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+type EventType string
+
+const (
+	Plan     EventType = "plan"
+	State    EventType = "state"
+	Queued   EventType = "queued"
+	Started  EventType = "started"
+	Pending  EventType = "pending"
+	Finished EventType = "finished"
+	Output   EventType = "output"
+	Error    EventType = "error"
+	Hook     EventType = "hook"
+)
+
+type Event struct {
+	Type     EventType         `json:"type"`
+	At       time.Time         `json:"at"`
+	TaskID   string            `json:"task,omitempty"`
+	Attempt  int               `json:"attempt,omitempty"`
+	Status   string            `json:"status,omitempty"`
+	Stream   string            `json:"stream,omitempty"`
+	Output   string            `json:"output,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	ExitCode *int              `json:"exit_code,omitempty"`
+	Node     string            `json:"node,omitempty"`
+	Hook     string            `json:"hook,omitempty"`
+	Plan     *planner.Snapshot `json:"plan,omitempty"`
+}
+
+type Journal struct {
+	plan    planner.Step
+	stater  status.Stater
+	writer  executor.Writer
+	encoder *json.Encoder
+
+	lock     sync.Mutex
+	snapshot planner.Snapshot
+	states   map[string]status.Type
+	hooks    map[string]bool
+}
+
+var _ status.Stater = &Journal{}
+var _ executor.Writer = &Journal{}
+var _ status.Memo = &Journal{}
+
+func NewJournal(
+	w io.Writer,
+	plan planner.Step,
+	stater status.Stater,
+	writer executor.Writer,
+) *Journal {
+	j := &Journal{
+		plan:    plan,
+		stater:  stater,
+		writer:  writer,
+		encoder: json.NewEncoder(w),
+		states:  map[string]status.Type{},
+		hooks:   map[string]bool{},
+	}
+
+	j.lock.Lock()
+	defer j.lock.Unlock()
+	j.recordTree(time.Now())
+	return j
+}
+
+func (j *Journal) Get(task status.Identifier) []status.Type {
+	return j.stater.Get(task)
+}
+
+func (j *Journal) Watch() (<-chan status.Event, func()) {
+	return j.stater.Watch()
+}
+
+func (j *Journal) Attempts(task status.Identifier) []status.Attempt {
+	return j.stater.Attempts(task)
+}
+
+func (j *Journal) Recall(key string) (bool, bool) {
+	if memo, ok := j.stater.(status.Memo); ok {
+		return memo.Recall(key)
+	}
+	return false, false
+}
+
+func (j *Journal) Remember(key string, value bool) bool {
+	if memo, ok := j.stater.(status.Memo); ok {
+		return memo.Remember(key, value)
+	}
+	return value
+}
+
+func (j *Journal) Add(task status.Identifier, s status.Type) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	err := j.stater.Add(task, s)
+	if err != nil {
+		return err
+	}
+
+	attempts := j.stater.Attempts(task)
+	latest := attempts[len(attempts)-1]
+	at := latest.Transitions[len(latest.Transitions)-1].At
+
+	ancestors := locate(j.plan.Tree(), []int{}, task.ID())
+	if s == status.Unstarted {
+		j.recordHooks(task, ancestors, at)
+	}
+	j.record(Event{
+		Type:    transitionType(s),
+		At:      at,
+		TaskID:  task.ID(),
+		Attempt: len(attempts),
+		Status:  s.String(),
+	})
+	j.recordChanges(ancestors, at)
+	return nil
+}
+
+func (j *Journal) AddError(task status.Identifier, err error) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	addErr := j.stater.AddError(task, err)
+	if addErr != nil {
+		return addErr
+	}
+
+	attempts := j.stater.Attempts(task)
+	latest := attempts[len(attempts)-1]
+	j.record(Event{
+		Type:     Error,
+		At:       time.Now(),
+		TaskID:   task.ID(),
+		Attempt:  len(attempts),
+		Error:    latest.Error,
+		ExitCode: latest.ExitCode,
+	})
+	return nil
+}
+
+func (j *Journal) GetWriter(task executor.Tasker) (io.Writer, io.Writer) {
+	stdout, stderr := j.writer.GetWriter(task)
+	return &chunks{journal: j, task: task, stream: "stdout", writer: stdout},
+		&chunks{journal: j, task: task, stream: "stderr", writer: stderr}
+}
+
+func (j *Journal) GetString(task executor.Tasker) (string, string) {
+	return j.writer.GetString(task)
+}
+
+func (j *Journal) recordTree(at time.Time) {
+	tree := j.plan.Tree()
+
+	snapshot := tree.Snapshot()
+	if !reflect.DeepEqual(snapshot, j.snapshot) {
+		j.snapshot = snapshot
+		j.record(Event{
+			Type: Plan,
+			At:   at,
+			Plan: &snapshot,
+		})
+	}
+
+	walk(tree, []int{}, func(node planner.Tree, path []int) {
+		j.recordState(node, path, at)
+	})
+}
+
+func (j *Journal) recordChanges(ancestors []located, at time.Time) {
+	if generator(ancestors) {
+		j.recordTree(at)
+		return
+	}
+
+	for _, ancestor := range ancestors {
+		j.recordState(ancestor.node, ancestor.path, at)
+		for i, child := range ancestor.node.Children() {
+			j.recordState(child, append(ancestor.path[:len(ancestor.path):len(ancestor.path)], i), at)
+		}
+	}
+}
+
+func (j *Journal) recordState(node planner.Tree, path []int, at time.Time) {
+	if node.Type() == planner.Task {
+		return
+	}
+
+	key, current := Key(path), node.State(j.stater)
+	if previous, ok := j.states[key]; ok && previous == current {
+		return
+	}
+	j.states[key] = current
+	j.record(Event{
+		Type:   State,
+		At:     at,
+		Node:   key,
+		Status: current.String(),
+	})
+}
+
+func (j *Journal) recordHooks(task status.Identifier, ancestors []located, at time.Time) {
+	for _, ancestor := range ancestors {
+		switch ancestor.node.Type() {
+		case planner.Success, planner.Failure, planner.Error, planner.Finally:
+		default:
+			continue
+		}
+
+		key := Key(ancestor.path)
+		if j.hooks[key] {
+			continue
+		}
+		j.hooks[key] = true
+		j.record(Event{
+			Type:   Hook,
+			At:     at,
+			TaskID: task.ID(),
+			Node:   key,
+			Hook:   ancestor.node.Type().String(),
+		})
+	}
+}
+
+func (j *Journal) record(event Event) {
+	err := j.encoder.Encode(event)
+	if err != nil {
+		log.Printf("could not record %s event in journal: %s", event.Type, err)
+	}
+}
+
+type chunks struct {
+	journal *Journal
+	task    executor.Tasker
+	stream  string
+	writer  io.Writer
+}
+
+func (c *chunks) Write(p []byte) (int, error) {
+	c.journal.lock.Lock()
+	defer c.journal.lock.Unlock()
+
+	c.journal.record(Event{
+		Type:   Output,
+		At:     time.Now(),
+		TaskID: c.task.ID(),
+		Stream: c.stream,
+		Output: string(p),
+	})
+	return c.writer.Write(p)
+}
+
+func Key(path []int) string {
+	parts := make([]string, len(path))
+	for i, index := range path {
+		parts[i] = fmt.Sprintf("%d", index)
+	}
+	return strings.Join(parts, "/")
+}
+
+func walk(tree planner.Tree, path []int, fun func(planner.Tree, []int)) {
+	fun(tree, path)
+	for i, child := range tree.Children() {
+		walk(child, append(path[:len(path):len(path)], i), fun)
+	}
+}
+
+type located struct {
+	node planner.Tree
+	path []int
+}
+
+func locate(tree planner.Tree, path []int, id string) []located {
+	if tree.Type() == planner.Task {
+		if tree.ID() == id {
+			return []located{{node: tree, path: path}}
+		}
+		return nil
+	}
+
+	for i, child := range tree.Children() {
+		if found := locate(child, append(path[:len(path):len(path)], i), id); found != nil {
+			return append([]located{{node: tree, path: path}}, found...)
+		}
+	}
+	return nil
+}
+
+func generator(ancestors []located) bool {
+	if len(ancestors) < 2 {
+		return false
+	}
+	parent, task := ancestors[len(ancestors)-2], ancestors[len(ancestors)-1]
+	return parent.node.Type() == planner.Generated && task.path[len(task.path)-1] == 0
+}
+
+func transitionType(s status.Type) EventType {
+	switch s {
+	case status.Unstarted:
+		return Queued
+	case status.Running:
+		return Started
+	case status.Pending:
+		return Pending
+	}
+	return Finished
+}
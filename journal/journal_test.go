@@ -0,0 +1,154 @@
+package journal_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http/httptest"
+	"strings"
+	"time"
+
+	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/executor/writers"
+	"github.com/jtarchie/dothings/journal"
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	"github.com/jtarchie/dothings/tasks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Journal", func() {
+	var (
+		plan     planner.Step
+		output   *bytes.Buffer
+		statuses status.Stater
+		inMemory executor.Writer
+	)
+
+	BeforeEach(func() {
+		plan, _ = planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(tasks.NewEcho("build", status.Success))
+			plan.Task(tasks.NewCommand("bash", "-c", "echo testing; exit 3"))
+			return plan.Failure(func(plan planner.Planner) error {
+				plan.Task(tasks.NewEcho("notify", status.Success))
+				return nil
+			})
+		})
+
+		output = &bytes.Buffer{}
+		recorder := journal.NewJournal(output, plan, status.NewStatuses(), writers.NewInMemory())
+		statuses, inMemory = recorder, recorder
+
+		Expect(executor.NewExecutorWithStater(plan, inMemory, statuses).Wait()).To(Equal(status.Failed))
+	})
+
+	events := func() []journal.Event {
+		events := []journal.Event{}
+		for _, line := range strings.Split(strings.TrimSpace(output.String()), "\n") {
+			var event journal.Event
+			Expect(json.Unmarshal([]byte(line), &event)).To(Succeed())
+			events = append(events, event)
+		}
+		return events
+	}
+
+	It("appends every event as a line of JSON", func() {
+		recorded := events()
+		Expect(recorded[0].Type).To(Equal(journal.Plan))
+		Expect(recorded[0].Plan.Children).To(HaveLen(3))
+
+		types := map[journal.EventType][]string{}
+		for _, event := range recorded {
+			types[event.Type] = append(types[event.Type], event.TaskID)
+		}
+		Expect(types[journal.Queued]).To(Equal([]string{"build", "command: bash -c echo testing; exit 3", "notify"}))
+		Expect(types[journal.Started]).To(HaveLen(3))
+		Expect(types[journal.Finished]).To(HaveLen(3))
+		Expect(types[journal.Error]).To(Equal([]string{"command: bash -c echo testing; exit 3"}))
+		Expect(types[journal.Hook]).To(Equal([]string{"notify"}))
+		Expect(types[journal.Output]).To(ContainElement("command: bash -c echo testing; exit 3"))
+
+		for i := 1; i < len(recorded); i++ {
+			Expect(recorded[i].At).To(BeTemporally(">=", recorded[i-1].At))
+		}
+	})
+
+	It("replays the stater and the writer", func() {
+		replay, err := journal.NewReplay(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		build, err := replay.Step(replay.Len())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(build.Plan.State(build.Stater)).To(Equal(status.Failed))
+		Expect(build.Plan.Tree().Snapshot()).To(Equal(plan.Tree().Snapshot()))
+
+		err = build.Plan.Tree().Walk(func(node planner.Tree, _ []planner.Tree) error {
+			if node.Type() != planner.Task {
+				return nil
+			}
+
+			Expect(build.Stater.Get(node.Task())).To(Equal(statuses.Get(node.Task())))
+			replayedAttempts, _ := json.Marshal(build.Stater.Attempts(node.Task()))
+			originalAttempts, _ := json.Marshal(statuses.Attempts(node.Task()))
+			Expect(replayedAttempts).To(MatchJSON(originalAttempts))
+
+			replayed, _ := build.Writer.GetString(node.Task())
+			original, _ := inMemory.GetString(node.Task())
+			Expect(replayed).To(Equal(original))
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("steps through time", func() {
+		replay, err := journal.NewReplay(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		build, err := replay.Step(0)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(build.Plan.State(build.Stater)).To(Equal(status.Unstarted))
+
+		hook := 0
+		for i, event := range replay.Events() {
+			if event.Type == journal.Hook {
+				hook = i
+			}
+		}
+		build, err = replay.Step(hook)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(build.Plan.State(build.Stater)).To(Equal(status.Running))
+		Expect(build.Stater.Get(tasks.NewEcho("build", status.Success))).To(Equal([]status.Type{status.Success}))
+		Expect(build.Stater.Get(tasks.NewEcho("notify", status.Success))).To(BeEmpty())
+
+		build, err = replay.Until(replay.Events()[0].At.Add(-time.Second))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(build.Stater.Get(tasks.NewEcho("build", status.Success))).To(BeEmpty())
+
+		_, err = replay.Step(replay.Len() + 1)
+		Expect(err).To(HaveOccurred())
+	})
+
+	It("serves a replay through the web handler", func() {
+		replay, err := journal.NewReplay(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		build, err := replay.Step(replay.Len())
+		Expect(err).NotTo(HaveOccurred())
+
+		handler := writers.NewWebHandlerWithClock(build.Plan, build.Writer, build.Stater, nil, func() time.Time { return build.At })
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+		body, err := ioutil.ReadAll(w.Result().Body)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(string(body)).To(ContainSubstring(`<div class="container failed">`))
+		Expect(string(body)).To(ContainSubstring(`<div class="error">exit status 3 (exit code 3)</div>`))
+		Expect(string(body)).To(ContainSubstring(`testing`))
+	})
+
+	It("errors on malformed journals", func() {
+		_, err := journal.NewReplay(strings.NewReader("{\n"))
+		Expect(err).To(MatchError(ContainSubstring("could not read journal event on line 1")))
+	})
+})
@@ -0,0 +1,138 @@
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/executor/writers"
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+type Replay struct {
+	events []Event
+}
+
+type Build struct {
+	Plan   planner.Step
+	Stater status.Stater
+	Writer executor.Writer
+	At     time.Time
+}
+
+func NewReplay(r io.Reader) (*Replay, error) {
+	events := []Event{}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 64*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		var event Event
+		err := json.Unmarshal(scanner.Bytes(), &event)
+		if err != nil {
+			return nil, fmt.Errorf("could not read journal event on line %d: %s", line, err)
+		}
+		events = append(events, event)
+	}
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("could not read journal: %s", err)
+	}
+
+	return &Replay{events: events}, nil
+}
+
+func (r *Replay) Events() []Event {
+	return r.events
+}
+
+func (r *Replay) Len() int {
+	return len(r.events)
+}
+
+func (r *Replay) Until(at time.Time) (*Build, error) {
+	steps := 0
+	for _, event := range r.events {
+		if event.At.After(at) {
+			break
+		}
+		steps++
+	}
+	return r.Step(steps)
+}
+
+func (r *Replay) Step(steps int) (*Build, error) {
+	if steps < 0 || len(r.events) < steps {
+		return nil, fmt.Errorf("could not replay %d events: journal has %d events", steps, len(r.events))
+	}
+
+	var now time.Time
+	stater := status.NewStatusesWithClock(func() time.Time {
+		return now
+	})
+	writer := writers.NewInMemory()
+	states := map[string]status.Type{}
+	snapshot := planner.Snapshot{Type: planner.Serial.String()}
+
+	for _, event := range r.events[:steps] {
+		now = event.At
+		task := planner.NewReplayedTask(event.TaskID)
+
+		switch event.Type {
+		case Plan:
+			if event.Plan != nil {
+				snapshot = *event.Plan
+			}
+		case State:
+			s, err := status.Parse(event.Status)
+			if err != nil {
+				return nil, fmt.Errorf("could not replay %s event: %s", event.Type, err)
+			}
+			states[event.Node] = s
+		case Queued, Started, Pending, Finished:
+			s, err := status.Parse(event.Status)
+			if err != nil {
+				return nil, fmt.Errorf("could not replay %s event: %s", event.Type, err)
+			}
+			err = stater.Add(task, s)
+			if err != nil {
+				return nil, fmt.Errorf("could not replay %s event for %s: %s", event.Type, event.TaskID, err)
+			}
+		case Error:
+			err := stater.AddError(task, status.NewError(event.Error, event.ExitCode))
+			if err != nil {
+				return nil, fmt.Errorf("could not replay error event for %s: %s", event.TaskID, err)
+			}
+		case Output:
+			stdout, stderr := writer.GetWriter(task)
+			if event.Stream == "stderr" {
+				stdout = stderr
+			}
+			_, _ = io.WriteString(stdout, event.Output)
+		case Hook:
+		default:
+			return nil, fmt.Errorf("could not replay event: unknown type '%s'", event.Type)
+		}
+	}
+
+	plan, err := planner.NewReplay(snapshot, func(path []int) status.Type {
+		return states[Key(path)]
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not replay plan: %s", err)
+	}
+
+	return &Build{
+		Plan:   plan,
+		Stater: stater,
+		Writer: writer,
+		At:     now,
+	}, nil
+}
@@ -0,0 +1,62 @@
+package planner
+
+import "github.com/jtarchie/dothings/status"
+
+func Cancelled(step Step, currentState status.Stater) Tasks {
+	tasks := Tasks{}
+
+	_ = step.Tree().Walk(func(node Tree, _ []Tree) error {
+		if p, ok := node.step.(*parallel); ok && p.failFast {
+			tasks = append(tasks, p.cancelled(currentState)...)
+		}
+		return nil
+	})
+
+	return tasks
+}
+
+func (p *parallel) cancelled(currentState status.Stater) Tasks {
+	tasks := Tasks{}
+
+	for currentAttempt := 1; currentAttempt <= p.attempts; currentAttempt++ {
+		if !p.failedFast(currentState, currentAttempt) {
+			continue
+		}
+
+		for _, step := range p.steps {
+			_ = step.Tree().Walk(func(node Tree, _ []Tree) error {
+				if node.Type() != Task {
+					return nil
+				}
+
+				states := currentState.Get(node.Task())
+				if len(states) != currentAttempt {
+					return nil
+				}
+
+				switch states[len(states)-1] {
+				case status.Unstarted, status.Running, status.Pending:
+					tasks = append(tasks, node.Task())
+				}
+				return nil
+			})
+		}
+	}
+
+	return tasks
+}
+
+func InHook(step Step, id string) bool {
+	path, ok := step.Tree().Path(id)
+	if !ok {
+		return false
+	}
+
+	for _, node := range path {
+		switch node.Type() {
+		case Failure, Error, Finally:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package planner_test
+
+import (
+	"github.com/jtarchie/dothings/planner"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("InHook", func() {
+	It("finds tasks within failure, error and finally hooks", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			err := plan.Success(func(plan planner.Planner) error {
+				plan.Task(task("passed"))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			err = plan.Failure(func(plan planner.Planner) error {
+				plan.Task(task("failed"))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			return plan.Finally(func(plan planner.Planner) error {
+				return plan.Serial(func(plan planner.Planner) error {
+					plan.Task(task("cleanup"))
+					return nil
+				})
+			})
+		})
+
+		Expect(planner.InHook(plan, "A")).To(BeFalse())
+		Expect(planner.InHook(plan, "passed")).To(BeFalse())
+		Expect(planner.InHook(plan, "failed")).To(BeTrue())
+		Expect(planner.InHook(plan, "cleanup")).To(BeTrue())
+		Expect(planner.InHook(plan, "unknown")).To(BeFalse())
+	})
+})
@@ -0,0 +1,117 @@
+package planner_test
+
+import (
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Fail fast", func() {
+	var plan planner.Step
+
+	BeforeEach(func() {
+		var err error
+		plan, err = planner.NewParallel(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			plan.Task(task("B"))
+			plan.Task(task("C"))
+			err := plan.Failure(func(plan planner.Planner) error {
+				plan.Task(task("D"))
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			return plan.Finally(func(plan planner.Planner) error {
+				plan.Task(task("E"))
+				return nil
+			})
+		}, planner.WithFailFast(), planner.WithMaxStepsInFlight(2))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("schedules children as usual until one fails", func() {
+		Expect(plan.Next(newStatuses())).To(EqualTasks([]task{"A", "B"}))
+
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{"B", "C"}))
+	})
+
+	It("stops scheduling children and runs the hooks once a child fails", func() {
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Failed)).ToNot(HaveOccurred())
+		Expect(state.Add(task("B"), status.Unstarted)).ToNot(HaveOccurred())
+
+		Expect(plan.Next(state)).To(EqualTasks([]task{"D"}))
+		Expect(plan.State(state)).To(Equal(status.Running))
+
+		Expect(state.Add(task("D"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{"E"}))
+
+		Expect(state.Add(task("E"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{}))
+		Expect(plan.State(state)).To(Equal(status.Failed))
+	})
+
+	It("resolves as errored when a child errors", func() {
+		plan, err := planner.NewParallel(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			plan.Task(task("B"))
+			return nil
+		}, planner.WithFailFast())
+		Expect(err).NotTo(HaveOccurred())
+
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Errored)).ToNot(HaveOccurred())
+		Expect(state.Add(task("B"), status.Unstarted)).ToNot(HaveOccurred())
+
+		Expect(plan.Next(state)).To(EqualTasks([]task{}))
+		Expect(plan.State(state)).To(Equal(status.Errored))
+	})
+
+	It("reports the in-flight children to cancel", func() {
+		state := status.NewStatuses()
+		for _, t := range []task{"A", "B"} {
+			Expect(state.Add(t, status.Unstarted)).ToNot(HaveOccurred())
+			Expect(state.Add(t, status.Running)).ToNot(HaveOccurred())
+		}
+		Expect(planner.Cancelled(plan, state)).To(EqualTasks([]task{}))
+
+		Expect(state.Add(task("A"), status.Failed)).ToNot(HaveOccurred())
+		Expect(planner.Cancelled(plan, state)).To(EqualTasks([]task{"B"}))
+	})
+
+	It("only cancels the children of the failed attempt", func() {
+		plan, err := planner.NewParallel(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			plan.Task(task("B"))
+			return nil
+		}, planner.WithFailFast(), planner.WithAttempts(2))
+		Expect(err).NotTo(HaveOccurred())
+
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Failed)).ToNot(HaveOccurred())
+		Expect(state.Add(task("B"), status.Running)).ToNot(HaveOccurred())
+		Expect(planner.Cancelled(plan, state)).To(EqualTasks([]task{"B"}))
+
+		Expect(state.Add(task("A"), status.Running)).ToNot(HaveOccurred())
+		Expect(state.Add(task("B"), status.Running)).ToNot(HaveOccurred())
+		Expect(planner.Cancelled(plan, state)).To(EqualTasks([]task{}))
+	})
+
+	It("moves on to the next attempt", func() {
+		plan, err := planner.NewParallel(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			plan.Task(task("B"))
+			return nil
+		}, planner.WithFailFast(), planner.WithAttempts(2))
+		Expect(err).NotTo(HaveOccurred())
+
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Failed)).ToNot(HaveOccurred())
+		Expect(state.Add(task("B"), status.Failed)).ToNot(HaveOccurred())
+
+		Expect(plan.Next(state)).To(EqualTasks([]task{"A", "B"}))
+		Expect(plan.State(state)).To(Equal(status.Running))
+	})
+})
@@ -0,0 +1,107 @@
+package planner
+
+import (
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/jtarchie/dothings/status"
+)
+
+type Generator interface {
+	ID() string
+	Generate(io.Writer, io.Writer) (func(Planner) error, error)
+}
+
+type generated struct {
+	unit Generator
+
+	sync.Mutex
+	sub Step
+}
+
+var _ Step = &generated{}
+var _ Tasker = &generated{}
+
+func (g *generated) ID() string {
+	return g.unit.ID()
+}
+
+func (g *generated) Execute(stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	fun, err := g.unit.Generate(stdout, stderr)
+	if err != nil {
+		return status.Errored, fmt.Errorf("could not generate plan: %s", err)
+	}
+
+	sub, err := NewSerial(fun)
+	if err != nil {
+		return status.Errored, fmt.Errorf("could not build generated plan: %s", err)
+	}
+
+	g.Lock()
+	defer g.Unlock()
+	g.sub = sub
+
+	return status.Success, nil
+}
+
+func (g *generated) generated() Step {
+	g.Lock()
+	defer g.Unlock()
+
+	return g.sub
+}
+
+func (g *generated) Tree() Tree {
+	nodes := []Tree{task{g}.Tree()}
+	if sub := g.generated(); sub != nil {
+		nodes = append(nodes, sub.Tree())
+	}
+
+	return Tree{
+		node:     Generated,
+		children: nodes,
+		attempts: 1,
+		step:     g,
+	}
+}
+
+func (g *generated) Next(currentState status.Stater, options ...stepOption) Tasks {
+	if state := (task{g}).State(currentState, options...); state != status.Success {
+		return task{g}.Next(currentState, options...)
+	}
+
+	if sub := g.generated(); sub != nil {
+		return sub.Next(currentState, options...)
+	}
+	return Tasks{}
+}
+
+func (g *generated) State(currentState status.Stater, options ...stepOption) status.Type {
+	if state := (task{g}).State(currentState, options...); state != status.Success {
+		return state
+	}
+
+	if sub := g.generated(); sub != nil {
+		return resolved(sub.State(currentState, options...))
+	}
+	return status.Running
+}
+
+func (p *plan) Generate(unit Generator) {
+	p.steps = append(p.steps, &generated{unit: unit})
+}
+
+func InGenerated(step Step, id string) bool {
+	path, ok := step.Tree().Path(id)
+	if !ok {
+		return false
+	}
+
+	for i, node := range path {
+		if node.Type() == Generated && i < len(path)-2 {
+			return true
+		}
+	}
+	return false
+}
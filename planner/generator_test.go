@@ -0,0 +1,115 @@
+package planner_test
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type generator struct {
+	id       string
+	packages []string
+	err      error
+}
+
+func (g generator) ID() string {
+	return g.id
+}
+
+func (g generator) Generate(stdout io.Writer, _ io.Writer) (func(planner.Planner) error, error) {
+	_, _ = fmt.Fprintf(stdout, "discovered %d packages\n", len(g.packages))
+	return func(plan planner.Planner) error {
+		return plan.Parallel(func(plan planner.Planner) error {
+			for _, name := range g.packages {
+				plan.Task(task(name))
+			}
+			return nil
+		})
+	}, g.err
+}
+
+var _ = Describe("Generator", func() {
+	var plan planner.Step
+
+	BeforeEach(func() {
+		plan, _ = planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			plan.Generate(generator{id: "discover", packages: []string{"B", "C"}})
+			plan.Task(task("D"))
+			return nil
+		})
+	})
+
+	It("runs the generator as a task", func() {
+		state := status.NewStatuses()
+		Expect(plan.Next(state)).To(EqualTasks([]task{"A"}))
+
+		Expect(state.Add(task("A"), status.Unstarted)).ToNot(HaveOccurred())
+		Expect(state.Add(task("A"), status.Running)).ToNot(HaveOccurred())
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+
+		next := plan.Next(state)
+		Expect(next).To(HaveLen(1))
+		Expect(next[0].ID()).To(Equal("discover"))
+		Expect(plan.State(state)).To(Equal(status.Running))
+	})
+
+	It("splices the generated plan in its place", func() {
+		state := status.NewStatuses()
+		Expect(state.Add(task("A"), status.Unstarted)).ToNot(HaveOccurred())
+		Expect(state.Add(task("A"), status.Running)).ToNot(HaveOccurred())
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+
+		discover := plan.Next(state)[0]
+		Expect(plan.Tree().Children()[1].Children()).To(HaveLen(1))
+
+		Expect(state.Add(discover, status.Unstarted)).ToNot(HaveOccurred())
+		Expect(state.Add(discover, status.Running)).ToNot(HaveOccurred())
+		Expect(discover.Execute(GinkgoWriter, GinkgoWriter)).To(Equal(status.Success))
+		Expect(state.Add(discover, status.Success)).ToNot(HaveOccurred())
+
+		Expect(plan.Next(state)).To(EqualTasks([]task{"B", "C"}))
+		Expect(plan.State(state)).To(Equal(status.Running))
+
+		tree := plan.Tree().Children()[1]
+		Expect(tree.Type()).To(Equal(planner.Generated))
+		Expect(tree.Children()).To(HaveLen(2))
+		Expect(tree.Children()[0].Task().ID()).To(Equal("discover"))
+		Expect(tree.Children()[1].Children()[0].Type()).To(Equal(planner.Parallel))
+
+		for _, t := range []task{"B", "C"} {
+			Expect(state.Add(t, status.Unstarted)).ToNot(HaveOccurred())
+			Expect(state.Add(t, status.Running)).ToNot(HaveOccurred())
+			Expect(state.Add(t, status.Success)).ToNot(HaveOccurred())
+		}
+		Expect(plan.Next(state)).To(EqualTasks([]task{"D"}))
+	})
+
+	It("knows which tasks were generated", func() {
+		Expect(planner.InGenerated(plan, "B")).To(BeFalse())
+
+		discover := plan.Tree().Children()[1].Children()[0].Task()
+		Expect(discover.Execute(GinkgoWriter, GinkgoWriter)).To(Equal(status.Success))
+
+		Expect(planner.InGenerated(plan, "A")).To(BeFalse())
+		Expect(planner.InGenerated(plan, "discover")).To(BeFalse())
+		Expect(planner.InGenerated(plan, "B")).To(BeTrue())
+		Expect(planner.InGenerated(plan, "unknown")).To(BeFalse())
+	})
+
+	It("errors when the plan cannot be generated", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Generate(generator{id: "discover", err: fmt.Errorf("oops")})
+			return nil
+		})
+
+		discover := plan.Next(newStatuses())[0]
+		state, err := discover.Execute(GinkgoWriter, GinkgoWriter)
+		Expect(state).To(Equal(status.Errored))
+		Expect(err).To(MatchError("could not generate plan: oops"))
+	})
+})
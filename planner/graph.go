@@ -0,0 +1,304 @@
+package planner
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jtarchie/dothings/status"
+)
+
+type Grapher interface {
+	Task(Tasker) *vertex
+	Serial(string, func(Planner) error, ...configOption) (*vertex, error)
+	Parallel(string, func(Planner) error, ...configOption) (*vertex, error)
+	Graph(string, func(Grapher) error, ...configOption) (*vertex, error)
+}
+
+type vertex struct {
+	id        string
+	step      Step
+	dependsOn []string
+	suffix    string
+}
+
+func (v *vertex) DependsOn(ids ...string) *vertex {
+	for _, id := range ids {
+		v.dependsOn = append(v.dependsOn, id+v.suffix)
+	}
+	return v
+}
+
+type graph struct {
+	config   *plan
+	vertices []*vertex
+	order    []*vertex
+}
+
+var _ Step = &graph{}
+var _ Grapher = &graph{}
+
+func (g *graph) Task(unit Tasker) *vertex {
+	v := &vertex{
+		id:   unit.ID(),
+		step: task{unit},
+	}
+	g.vertices = append(g.vertices, v)
+	return v
+}
+
+func (g *graph) Serial(id string, fun func(Planner) error, options ...configOption) (*vertex, error) {
+	p := newPlan()
+	err := p.Serial(fun, options...)
+	if err != nil {
+		return nil, err
+	}
+	return g.add(id, p.steps[0]), nil
+}
+
+func (g *graph) Parallel(id string, fun func(Planner) error, options ...configOption) (*vertex, error) {
+	p := newPlan()
+	err := p.Parallel(fun, options...)
+	if err != nil {
+		return nil, err
+	}
+	return g.add(id, p.steps[0]), nil
+}
+
+func (g *graph) Graph(id string, fun func(Grapher) error, options ...configOption) (*vertex, error) {
+	p := newPlan()
+	err := p.Graph(fun, options...)
+	if err != nil {
+		return nil, err
+	}
+	return g.add(id, p.steps[0]), nil
+}
+
+func (g *graph) add(id string, step Step) *vertex {
+	v := &vertex{
+		id:   id,
+		step: step,
+	}
+	g.vertices = append(g.vertices, v)
+	return v
+}
+
+func (g *graph) Tree() Tree {
+	nodes := []Tree{}
+	for _, v := range g.vertices {
+		node := v.step.Tree()
+		node.id = v.id
+		node.dependsOn = v.dependsOn
+		nodes = append(nodes, node)
+	}
+	return Tree{
+		node:     Graph,
+		children: nodes,
+		attempts: g.config.attempts,
+		maxSteps: g.config.maxSteps,
+		backoff:  g.config.backoff,
+		locks:    g.config.locks,
+		step:     g,
+	}
+}
+
+func (g *graph) Next(currentState status.Stater, _ ...stepOption) Tasks {
+	currentAttempt := g.currentAttempt(currentState)
+	states, blocked := g.states(currentState, currentAttempt, true)
+
+	names := Tasks{}
+	for _, v := range g.vertices {
+		if blocked[v.id] {
+			continue
+		}
+
+		ready := true
+		for _, id := range v.dependsOn {
+			if states[id] != status.Success {
+				ready = false
+				break
+			}
+		}
+
+		if ready {
+			names = append(names, v.step.Next(currentState, withCurrentAttempt(currentAttempt))...)
+		}
+	}
+
+	if g.config.maxSteps > 0 && len(names) >= g.config.maxSteps {
+		return names[0:g.config.maxSteps]
+	}
+
+	sort.Sort(names)
+	return names
+}
+
+func (g *graph) State(currentState status.Stater, options ...stepOption) status.Type {
+	s := &step{}
+	for _, o := range options {
+		o(s)
+	}
+	return g.status(currentState, g.currentAttempt(currentState), s.runnable)
+}
+
+func (g *graph) currentAttempt(currentState status.Stater) int {
+	for currentAttempt := 1; currentAttempt < g.config.attempts; currentAttempt++ {
+		switch state := g.status(currentState, currentAttempt, false); state {
+		case status.Failed, status.Errored:
+			if g.config.retries(state) {
+				continue
+			}
+		}
+		return currentAttempt
+	}
+	return g.config.attempts
+}
+
+func (g *graph) states(currentState status.Stater, currentAttempt int, runnable bool) (map[string]status.Type, map[string]bool) {
+	states, blocked := map[string]status.Type{}, map[string]bool{}
+
+	for _, v := range g.order {
+		for _, id := range v.dependsOn {
+			if blocked[id] || states[id] == status.Failed || states[id] == status.Errored {
+				blocked[v.id] = true
+				break
+			}
+		}
+
+		if !blocked[v.id] {
+			reached := runnable
+			for _, id := range v.dependsOn {
+				reached = reached && states[id] == status.Success
+			}
+			states[v.id] = resolved(v.step.State(currentState, withCurrentAttempt(currentAttempt), withRunnable(reached)))
+		}
+	}
+
+	return states, blocked
+}
+
+func (g *graph) status(currentState status.Stater, currentAttempt int, runnable bool) status.Type {
+	states, blocked := g.states(currentState, currentAttempt, runnable)
+
+	statuses := map[status.Type]int{}
+	for _, v := range g.vertices {
+		if !blocked[v.id] {
+			statuses[states[v.id]]++
+		}
+	}
+
+	if len(statuses) == 0 {
+		return status.Success
+	}
+
+	if len(statuses) == 1 {
+		for status := range statuses {
+			return status
+		}
+	}
+
+	if _, ok := statuses[status.Unstarted]; ok {
+		return status.Running
+	}
+
+	if _, ok := statuses[status.Running]; ok {
+		return status.Running
+	}
+
+	if _, ok := statuses[status.Errored]; ok {
+		return status.Errored
+	}
+
+	return status.Failed
+}
+
+func (g *graph) sort() error {
+	vertices := map[string]*vertex{}
+	for _, v := range g.vertices {
+		if _, ok := vertices[v.id]; ok {
+			return fmt.Errorf("duplicate graph node '%s'", v.id)
+		}
+		vertices[v.id] = v
+	}
+
+	for _, v := range g.vertices {
+		for _, id := range v.dependsOn {
+			if _, ok := vertices[id]; !ok {
+				return fmt.Errorf("graph node '%s' depends on unknown node '%s'", v.id, id)
+			}
+		}
+	}
+
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+
+	marks := map[string]int{}
+	g.order = []*vertex{}
+
+	var visit func(v *vertex, path []string) error
+	visit = func(v *vertex, path []string) error {
+		path = append(path, v.id)
+		switch marks[v.id] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle detected: %s", strings.Join(path, " -> "))
+		}
+
+		marks[v.id] = visiting
+		for _, id := range v.dependsOn {
+			err := visit(vertices[id], path)
+			if err != nil {
+				return err
+			}
+		}
+		marks[v.id] = visited
+		g.order = append(g.order, v)
+		return nil
+	}
+
+	for _, v := range g.vertices {
+		err := visit(v, []string{})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func newGraph(fun func(Grapher) error, options ...configOption) (*graph, error) {
+	g := &graph{
+		config: newPlan(),
+	}
+
+	for _, o := range options {
+		o(g.config)
+	}
+
+	err := fun(g)
+	if err != nil {
+		return nil, err
+	}
+
+	err = g.sort()
+	if err != nil {
+		return nil, err
+	}
+
+	if g.config.validate {
+		err = validated(g)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return g, nil
+}
+
+func NewGraph(fun func(Grapher) error, options ...configOption) (Step, error) {
+	return newGraph(fun, options...)
+}
@@ -0,0 +1,209 @@
+package planner_test
+
+import (
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Graph", func() {
+	var plan planner.Step
+
+	BeforeEach(func() {
+		var err error
+		plan, err = planner.NewGraph(func(graph planner.Grapher) error {
+			graph.Task(task("A"))
+			graph.Task(task("B"))
+			graph.Task(task("C")).DependsOn("A", "B")
+			graph.Task(task("D")).DependsOn("A")
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("returns every task without dependencies", func() {
+		Expect(plan.Next(newStatuses())).To(EqualTasks([]task{"A", "B"}))
+		Expect(plan.State(newStatuses())).To(Equal(status.Unstarted))
+	})
+
+	It("returns tasks once all their dependencies succeeded", func() {
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+
+		Expect(plan.Next(state)).To(EqualTasks([]task{"B", "D"}))
+		Expect(plan.State(state)).To(Equal(status.Running))
+
+		Expect(state.Add(task("B"), status.Success)).ToNot(HaveOccurred())
+
+		Expect(plan.Next(state)).To(EqualTasks([]task{"C", "D"}))
+		Expect(plan.State(state)).To(Equal(status.Running))
+
+		Expect(state.Add(task("C"), status.Success)).ToNot(HaveOccurred())
+		Expect(state.Add(task("D"), status.Success)).ToNot(HaveOccurred())
+
+		Expect(plan.Next(state)).To(EqualTasks([]task{}))
+		Expect(plan.State(state)).To(Equal(status.Success))
+	})
+
+	It("skips the dependents of a failed task", func() {
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+		Expect(state.Add(task("B"), status.Failed)).ToNot(HaveOccurred())
+
+		Expect(plan.Next(state)).To(EqualTasks([]task{"D"}))
+		Expect(plan.State(state)).To(Equal(status.Running))
+
+		Expect(state.Add(task("D"), status.Success)).ToNot(HaveOccurred())
+
+		Expect(plan.Next(state)).To(EqualTasks([]task{}))
+		Expect(plan.State(state)).To(Equal(status.Failed))
+	})
+
+	It("skips transitive dependents of an errored task", func() {
+		plan, err := planner.NewGraph(func(graph planner.Grapher) error {
+			graph.Task(task("A"))
+			graph.Task(task("B")).DependsOn("A")
+			graph.Task(task("C")).DependsOn("B")
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Errored)).ToNot(HaveOccurred())
+
+		Expect(plan.Next(state)).To(EqualTasks([]task{}))
+		Expect(plan.State(state)).To(Equal(status.Errored))
+	})
+
+	It("reruns the graph on failure with attempts", func() {
+		plan, err := planner.NewGraph(func(graph planner.Grapher) error {
+			graph.Task(task("A"))
+			graph.Task(task("B")).DependsOn("A")
+			return nil
+		}, planner.WithAttempts(2))
+		Expect(err).NotTo(HaveOccurred())
+
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Failed)).ToNot(HaveOccurred())
+
+		Expect(plan.Next(state)).To(EqualTasks([]task{"A"}))
+		Expect(plan.State(state)).To(Equal(status.Running))
+
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+
+		Expect(plan.Next(state)).To(EqualTasks([]task{"B"}))
+		Expect(plan.State(state)).To(Equal(status.Running))
+	})
+
+	It("limits the number of steps returned at a time", func() {
+		plan, err := planner.NewGraph(func(graph planner.Grapher) error {
+			graph.Task(task("A"))
+			graph.Task(task("B"))
+			graph.Task(task("C"))
+			return nil
+		}, planner.WithMaxStepsInFlight(2))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(plan.Next(newStatuses())).To(EqualTasks([]task{"A", "B"}))
+	})
+
+	It("nests serial and parallel steps as nodes", func() {
+		plan, err := planner.NewGraph(func(graph planner.Grapher) error {
+			_, err := graph.Serial("build", func(plan planner.Planner) error {
+				plan.Task(task("A"))
+				plan.Task(task("B"))
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			test, err := graph.Parallel("test", func(plan planner.Planner) error {
+				plan.Task(task("C"))
+				plan.Task(task("D"))
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			test.DependsOn("build")
+
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		state := newStatuses()
+		Expect(plan.Next(state)).To(EqualTasks([]task{"A"}))
+
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{"B"}))
+
+		Expect(state.Add(task("B"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{"C", "D"}))
+
+		tree := plan.Tree()
+		Expect(tree.Type()).To(Equal(planner.Graph))
+		Expect(tree.Children()[0].Type()).To(Equal(planner.Serial))
+		Expect(tree.Children()[0].ID()).To(Equal("build"))
+		Expect(tree.Children()[1].ID()).To(Equal("test"))
+		Expect(tree.Children()[1].DependsOn()).To(Equal([]string{"build"}))
+	})
+
+	It("nests inside serial plans", func() {
+		plan, err := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			err := plan.Graph(func(graph planner.Grapher) error {
+				graph.Task(task("B"))
+				graph.Task(task("C")).DependsOn("B")
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			plan.Task(task("D"))
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{"B"}))
+
+		Expect(state.Add(task("B"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{"C"}))
+
+		Expect(state.Add(task("C"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{"D"}))
+	})
+
+	When("constructing an invalid graph", func() {
+		It("detects cycles", func() {
+			_, err := planner.NewGraph(func(graph planner.Grapher) error {
+				graph.Task(task("A")).DependsOn("C")
+				graph.Task(task("B")).DependsOn("A")
+				graph.Task(task("C")).DependsOn("B")
+				return nil
+			})
+			Expect(err).To(MatchError("dependency cycle detected: A -> C -> B -> A"))
+		})
+
+		It("detects unknown dependencies", func() {
+			_, err := planner.NewGraph(func(graph planner.Grapher) error {
+				graph.Task(task("A")).DependsOn("Z")
+				return nil
+			})
+			Expect(err).To(MatchError("graph node 'A' depends on unknown node 'Z'"))
+		})
+
+		It("detects duplicate nodes", func() {
+			err := newPlanWithGraph(func(graph planner.Grapher) error {
+				graph.Task(task("A"))
+				graph.Task(task("A"))
+				return nil
+			})
+			Expect(err).To(MatchError("could not create graph step: duplicate graph node 'A'"))
+		})
+	})
+})
+
+func newPlanWithGraph(fun func(planner.Grapher) error) error {
+	_, err := planner.NewSerial(func(plan planner.Planner) error {
+		return plan.Graph(fun)
+	})
+	return err
+}
@@ -0,0 +1,67 @@
+package planner
+
+import (
+	"github.com/jtarchie/dothings/status"
+)
+
+type Locker interface {
+	Locks() []string
+}
+
+func WithLocks(names ...string) func(p *plan) {
+	return func(p *plan) {
+		p.locks = append(p.locks, names...)
+	}
+}
+
+type Hold struct {
+	Name string
+	node Tree
+}
+
+func (h Hold) owner() interface{} {
+	if h.node.Type() == Task {
+		return h.node.ID()
+	}
+	return h.node.step
+}
+
+func (h Hold) Same(other Hold) bool {
+	return h.Name == other.Name && h.owner() == other.owner()
+}
+
+func (h Hold) Released(currentState status.Stater) bool {
+	if h.node.Type() == Task {
+		states := currentState.Get(h.node.Task())
+		return len(states) == 0 || finished(states[len(states)-1])
+	}
+	return finished(h.node.State(currentState))
+}
+
+func finished(s status.Type) bool {
+	return s != status.Unstarted && s != status.Running
+}
+
+func Holds(step Step, unit Tasker) []Hold {
+	path, ok := step.Tree().Path(unit.ID())
+	if !ok {
+		return nil
+	}
+
+	holds := []Hold{}
+	for _, node := range path {
+		for _, name := range node.locks {
+			holds = append(holds, Hold{Name: name, node: node})
+		}
+	}
+	return holds
+}
+
+func taskLocks(unit Tasker) []string {
+	for _, unit := range Unwrap(unit) {
+		if locker, ok := unit.(Locker); ok {
+			return locker.Locks()
+		}
+	}
+	return nil
+}
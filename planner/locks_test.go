@@ -0,0 +1,91 @@
+package planner_test
+
+import (
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+type lockingTask struct {
+	task
+	locks []string
+}
+
+func (i lockingTask) Locks() []string {
+	return i.locks
+}
+
+var _ = Describe("Locks", func() {
+	var plan planner.Step
+
+	BeforeEach(func() {
+		plan, _ = planner.NewParallel(func(plan planner.Planner) error {
+			err := plan.Serial(func(plan planner.Planner) error {
+				plan.Task(task("A"))
+				plan.Task(lockingTask{task: "B", locks: []string{"database"}})
+				return nil
+			}, planner.WithLocks("staging"))
+			if err != nil {
+				return err
+			}
+			plan.Task(task("C"))
+			return nil
+		})
+	})
+
+	It("exposes the locks on the tree", func() {
+		tree := plan.Tree()
+		Expect(tree.Locks()).To(BeEmpty())
+		Expect(tree.Children()[0].Locks()).To(Equal([]string{"staging"}))
+		Expect(tree.Children()[0].Children()[1].Locks()).To(Equal([]string{"database"}))
+	})
+
+	It("collects the locks a task needs from its ancestors", func() {
+		holds := planner.Holds(plan, task("A"))
+		Expect(holds).To(HaveLen(1))
+		Expect(holds[0].Name).To(Equal("staging"))
+
+		holds = planner.Holds(plan, task("B"))
+		Expect(holds).To(HaveLen(2))
+		Expect(holds[0].Name).To(Equal("staging"))
+		Expect(holds[1].Name).To(Equal("database"))
+
+		Expect(planner.Holds(plan, task("C"))).To(BeEmpty())
+	})
+
+	It("treats holds from the same step as the same", func() {
+		a := planner.Holds(plan, task("A"))
+		b := planner.Holds(plan, task("B"))
+		Expect(a[0].Same(b[0])).To(BeTrue())
+		Expect(b[0].Same(b[1])).To(BeFalse())
+	})
+
+	It("releases a hold once its step has finished", func() {
+		state := status.NewStatuses()
+		hold := planner.Holds(plan, task("A"))[0]
+		Expect(hold.Released(state)).To(BeFalse())
+
+		for _, t := range []task{"A", "B"} {
+			Expect(state.Add(t, status.Unstarted)).ToNot(HaveOccurred())
+			Expect(state.Add(t, status.Running)).ToNot(HaveOccurred())
+			Expect(hold.Released(state)).To(BeFalse())
+			Expect(state.Add(t, status.Success)).ToNot(HaveOccurred())
+		}
+		Expect(hold.Released(state)).To(BeTrue())
+	})
+
+	It("releases a task hold once its latest attempt has finished", func() {
+		state := status.NewStatuses()
+		hold := planner.Holds(plan, task("B"))[1]
+		Expect(hold.Released(state)).To(BeTrue())
+
+		Expect(state.Add(task("B"), status.Unstarted)).ToNot(HaveOccurred())
+		Expect(hold.Released(state)).To(BeFalse())
+		Expect(state.Add(task("B"), status.Running)).ToNot(HaveOccurred())
+		Expect(state.Add(task("B"), status.Failed)).ToNot(HaveOccurred())
+		Expect(hold.Released(state)).To(BeTrue())
+		Expect(state.Add(task("B"), status.Unstarted)).ToNot(HaveOccurred())
+		Expect(hold.Released(state)).To(BeFalse())
+	})
+})
@@ -0,0 +1,272 @@
+package planner
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+type combination map[string]string
+
+func (c combination) matches(rule combination) bool {
+	for name, value := range rule {
+		if c[name] != value {
+			return false
+		}
+	}
+	return true
+}
+
+func (c combination) suffix() string {
+	names := []string{}
+	for name := range c {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := []string{}
+	for _, name := range names {
+		pairs = append(pairs, fmt.Sprintf("%s=%s", name, c[name]))
+	}
+	return fmt.Sprintf(" [%s]", strings.Join(pairs, ", "))
+}
+
+func WithInclude(values map[string]string) func(p *plan) {
+	return func(p *plan) {
+		p.include = append(p.include, values)
+	}
+}
+
+func WithExclude(rule map[string]string) func(p *plan) {
+	return func(p *plan) {
+		p.exclude = append(p.exclude, rule)
+	}
+}
+
+func expand(axes map[string][]string, include []combination, exclude []combination) []combination {
+	names := []string{}
+	for name := range axes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	combinations := []combination{{}}
+	for _, name := range names {
+		expanded := []combination{}
+		for _, values := range combinations {
+			for _, value := range axes[name] {
+				c := combination{name: value}
+				for k, v := range values {
+					c[k] = v
+				}
+				expanded = append(expanded, c)
+			}
+		}
+		combinations = expanded
+	}
+
+	filtered := []combination{}
+	seen := map[string]bool{}
+	for _, c := range append(combinations, include...) {
+		excluded := false
+		for _, rule := range exclude {
+			if c.matches(rule) {
+				excluded = true
+				break
+			}
+		}
+		if excluded || seen[c.suffix()] || len(c) == 0 {
+			continue
+		}
+
+		seen[c.suffix()] = true
+		filtered = append(filtered, c)
+	}
+	return filtered
+}
+
+type cell struct {
+	*serial
+	id string
+}
+
+func (p *cell) Tree() Tree {
+	tree := p.serial.Tree()
+	tree.id = p.id
+	return tree
+}
+
+func (p *plan) Matrix(axes map[string][]string, fun func(Planner, map[string]string) error, options ...configOption) error {
+	plan := &parallel{newPlan()}
+
+	for _, o := range options {
+		o(plan.plan)
+	}
+
+	combinations := expand(axes, plan.include, plan.exclude)
+	if len(combinations) == 0 {
+		return fmt.Errorf("could not create matrix step: matrix expanded to no combinations")
+	}
+
+	for _, c := range combinations {
+		step := &serial{newPlan()}
+		err := fun(&suffixedPlanner{step, c.suffix()}, c)
+		if err != nil {
+			return fmt.Errorf("could not create matrix step%s: %s", c.suffix(), err)
+		}
+		plan.steps = append(plan.steps, &cell{step, strings.TrimSpace(c.suffix())})
+	}
+
+	if plan.validate {
+		err := validated(plan)
+		if err != nil {
+			return fmt.Errorf("could not create matrix step: %s", err)
+		}
+	}
+	p.steps = append(p.steps, plan)
+	return nil
+}
+
+type suffixedTask struct {
+	Tasker
+	suffix string
+}
+
+func (t suffixedTask) ID() string {
+	return t.Tasker.ID() + t.suffix
+}
+
+func (t suffixedTask) Unwrap() Tasker {
+	return t.Tasker
+}
+
+type suffixedPlanner struct {
+	Planner
+	suffix string
+}
+
+func (s *suffixedPlanner) wrap(fun func(Planner) error) func(Planner) error {
+	return func(plan Planner) error {
+		return fun(&suffixedPlanner{plan, s.suffix})
+	}
+}
+
+func (s *suffixedPlanner) Task(unit Tasker) task {
+	return s.Planner.Task(suffixedTask{unit, s.suffix})
+}
+
+func (s *suffixedPlanner) Parallel(fun func(Planner) error, options ...configOption) error {
+	return s.Planner.Parallel(s.wrap(fun), options...)
+}
+
+func (s *suffixedPlanner) Serial(fun func(Planner) error, options ...configOption) error {
+	return s.Planner.Serial(s.wrap(fun), options...)
+}
+
+func (s *suffixedPlanner) When(predicate Predicate, fun func(Planner) error, options ...configOption) error {
+	return s.Planner.When(predicate, s.wrap(fun), options...)
+}
+
+func (s *suffixedPlanner) Matrix(axes map[string][]string, fun func(Planner, map[string]string) error, options ...configOption) error {
+	return s.Planner.Matrix(axes, func(plan Planner, c map[string]string) error {
+		return fun(&suffixedPlanner{plan, s.suffix}, c)
+	}, options...)
+}
+
+func (s *suffixedPlanner) Success(fun func(Planner) error) error {
+	return s.Planner.Success(s.wrap(fun))
+}
+
+func (s *suffixedPlanner) Failure(fun func(Planner) error) error {
+	return s.Planner.Failure(s.wrap(fun))
+}
+
+func (s *suffixedPlanner) Finally(fun func(Planner) error) error {
+	return s.Planner.Finally(s.wrap(fun))
+}
+
+func (s *suffixedPlanner) Error(fun func(Planner) error) error {
+	return s.Planner.Error(s.wrap(fun))
+}
+
+func (s *suffixedPlanner) Try(fun func(Planner) error) error {
+	return s.Planner.Try(s.wrap(fun))
+}
+
+func (s *suffixedPlanner) Graph(fun func(Grapher) error, options ...configOption) error {
+	return s.Planner.Graph(func(graph Grapher) error {
+		return fun(&suffixedGrapher{graph, s.suffix})
+	}, options...)
+}
+
+type suffixedGrapher struct {
+	Grapher
+	suffix string
+}
+
+func (s *suffixedGrapher) suffixed(v *vertex) *vertex {
+	if v != nil {
+		v.suffix = s.suffix
+	}
+	return v
+}
+
+func (s *suffixedGrapher) wrap(fun func(Planner) error) func(Planner) error {
+	return func(plan Planner) error {
+		return fun(&suffixedPlanner{plan, s.suffix})
+	}
+}
+
+func (s *suffixedGrapher) Task(unit Tasker) *vertex {
+	return s.suffixed(s.Grapher.Task(suffixedTask{unit, s.suffix}))
+}
+
+func (s *suffixedGrapher) Serial(id string, fun func(Planner) error, options ...configOption) (*vertex, error) {
+	v, err := s.Grapher.Serial(id+s.suffix, s.wrap(fun), options...)
+	return s.suffixed(v), err
+}
+
+func (s *suffixedGrapher) Parallel(id string, fun func(Planner) error, options ...configOption) (*vertex, error) {
+	v, err := s.Grapher.Parallel(id+s.suffix, s.wrap(fun), options...)
+	return s.suffixed(v), err
+}
+
+func (s *suffixedGrapher) Graph(id string, fun func(Grapher) error, options ...configOption) (*vertex, error) {
+	v, err := s.Grapher.Graph(id+s.suffix, func(graph Grapher) error {
+		return fun(&suffixedGrapher{graph, s.suffix})
+	}, options...)
+	return s.suffixed(v), err
+}
+
+func NewMatrix(axes map[string][]string, fun func(Planner, map[string]string) error, options ...configOption) (Step, error) {
+	plan := newPlan()
+	err := plan.Matrix(axes, fun, options...)
+	if err != nil {
+		return nil, err
+	}
+	return plan.steps[0], nil
+}
+
+type suffixedGenerator struct {
+	Generator
+	suffix string
+}
+
+func (g suffixedGenerator) ID() string {
+	return g.Generator.ID() + g.suffix
+}
+
+func (g suffixedGenerator) Generate(stdout io.Writer, stderr io.Writer) (func(Planner) error, error) {
+	fun, err := g.Generator.Generate(stdout, stderr)
+	if err != nil {
+		return nil, err
+	}
+	return func(plan Planner) error {
+		return fun(&suffixedPlanner{plan, g.suffix})
+	}, nil
+}
+
+func (s *suffixedPlanner) Generate(unit Generator) {
+	s.Planner.Generate(suffixedGenerator{unit, s.suffix})
+}
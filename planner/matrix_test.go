@@ -0,0 +1,143 @@
+package planner_test
+
+import (
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Matrix", func() {
+	axes := map[string][]string{
+		"os": {"linux", "darwin"},
+		"go": {"1.12", "1.13"},
+	}
+
+	ids := func(tasks planner.Tasks) []string {
+		names := []string{}
+		for _, t := range tasks {
+			names = append(names, t.ID())
+		}
+		return names
+	}
+
+	It("expands every combination into a parallel group", func() {
+		combinations := []map[string]string{}
+		plan, err := planner.NewMatrix(axes, func(plan planner.Planner, c map[string]string) error {
+			combinations = append(combinations, c)
+			plan.Task(task("test"))
+			plan.Task(task("lint"))
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(combinations).To(Equal([]map[string]string{
+			{"go": "1.12", "os": "linux"},
+			{"go": "1.12", "os": "darwin"},
+			{"go": "1.13", "os": "linux"},
+			{"go": "1.13", "os": "darwin"},
+		}))
+
+		Expect(ids(plan.Next(newStatuses()))).To(Equal([]string{
+			"test [go=1.12, os=darwin]",
+			"test [go=1.12, os=linux]",
+			"test [go=1.13, os=darwin]",
+			"test [go=1.13, os=linux]",
+		}))
+
+		tree := plan.Tree()
+		Expect(tree.Type()).To(Equal(planner.Parallel))
+		Expect(tree.Children()).To(HaveLen(4))
+		Expect(tree.Children()[0].Type()).To(Equal(planner.Serial))
+		Expect(tree.Children()[0].ID()).To(Equal("[go=1.12, os=linux]"))
+		Expect(tree.Children()[0].Children()[1].Task().ID()).To(Equal("lint [go=1.12, os=linux]"))
+	})
+
+	It("lets the original task be unwrapped", func() {
+		plan, err := planner.NewMatrix(map[string][]string{"os": {"linux"}}, func(plan planner.Planner, c map[string]string) error {
+			plan.Task(task("test"))
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		units := planner.Unwrap(plan.Next(status.NewStatuses())[0])
+		Expect(units).To(HaveLen(2))
+		Expect(units[0].ID()).To(Equal("test [os=linux]"))
+		Expect(units[1]).To(Equal(task("test")))
+	})
+
+	It("runs the steps of each combination serially", func() {
+		plan, err := planner.NewMatrix(map[string][]string{"os": {"linux"}}, func(plan planner.Planner, c map[string]string) error {
+			plan.Task(task("test"))
+			return plan.Parallel(func(plan planner.Planner) error {
+				plan.Task(task("lint"))
+				return nil
+			})
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		state := status.NewStatuses()
+		next := plan.Next(state)
+		Expect(ids(next)).To(Equal([]string{"test [os=linux]"}))
+
+		Expect(state.Add(next[0], status.Unstarted)).ToNot(HaveOccurred())
+		Expect(state.Add(next[0], status.Running)).ToNot(HaveOccurred())
+		Expect(state.Add(next[0], status.Success)).ToNot(HaveOccurred())
+		Expect(ids(plan.Next(state))).To(Equal([]string{"lint [os=linux]"}))
+	})
+
+	It("applies include and exclude rules", func() {
+		plan, err := planner.NewMatrix(axes, func(plan planner.Planner, c map[string]string) error {
+			plan.Task(task("test"))
+			return nil
+		},
+			planner.WithExclude(map[string]string{"os": "darwin"}),
+			planner.WithExclude(map[string]string{"go": "1.12", "os": "linux"}),
+			planner.WithInclude(map[string]string{"go": "1.14", "os": "windows"}),
+			planner.WithInclude(map[string]string{"go": "1.13", "os": "linux"}),
+		)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ids(plan.Next(newStatuses()))).To(Equal([]string{
+			"test [go=1.13, os=linux]",
+			"test [go=1.14, os=windows]",
+		}))
+	})
+
+	It("fails when every combination is excluded", func() {
+		_, err := planner.NewMatrix(map[string][]string{"os": {"linux"}}, func(plan planner.Planner, c map[string]string) error {
+			plan.Task(task("test"))
+			return nil
+		}, planner.WithExclude(map[string]string{"os": "linux"}))
+		Expect(err).To(MatchError(ContainSubstring("matrix expanded to no combinations")))
+
+		_, err = planner.NewMatrix(map[string][]string{"os": {}}, func(plan planner.Planner, c map[string]string) error {
+			plan.Task(task("test"))
+			return nil
+		})
+		Expect(err).To(MatchError(ContainSubstring("matrix expanded to no combinations")))
+	})
+
+	It("suffixes graph nodes and their dependencies", func() {
+		plan, err := planner.NewMatrix(map[string][]string{"os": {"linux"}}, func(plan planner.Planner, c map[string]string) error {
+			return plan.Graph(func(graph planner.Grapher) error {
+				graph.Task(task("A"))
+				graph.Task(task("B")).DependsOn("A")
+				return nil
+			})
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(ids(plan.Next(newStatuses()))).To(Equal([]string{"A [os=linux]"}))
+	})
+
+	It("applies options to the parallel group", func() {
+		plan, err := planner.NewMatrix(axes, func(plan planner.Planner, c map[string]string) error {
+			plan.Task(task("test"))
+			return nil
+		}, planner.WithMaxStepsInFlight(2))
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(plan.Next(newStatuses())).To(HaveLen(2))
+	})
+})
@@ -13,7 +13,9 @@ type parallel struct {
 var _ Step = &parallel{}
 
 func (p *parallel) Tree() Tree {
-	return p.tree(Parallel)
+	tree := p.tree(Parallel)
+	tree.step = p
+	return tree
 }
 
 func (p *parallel) Next(currentState status.Stater, options ...stepOption) Tasks {
@@ -26,17 +28,25 @@ func (p *parallel) Next(currentState status.Stater, options ...stepOption) Tasks
 
 	names := Tasks{}
 	for currentAttempt := 1; currentAttempt <= p.attempts; currentAttempt++ {
-		for _, step := range p.steps {
-			names = append(names, step.Next(currentState, withCurrentAttempt(currentAttempt))...)
+		s.currentAttempt = currentAttempt
+		statuses := p.attemptStatuses(currentState, currentAttempt, true)
+
+		if !p.failFast || !failedIn(statuses) {
+			for _, step := range p.steps {
+				names = append(names, step.Next(currentState, withCurrentAttempt(currentAttempt))...)
+			}
+
+			if len(names) > 0 {
+				break
+			}
 		}
 
-		s.currentAttempt = currentAttempt
-		if len(names) > 0 {
+		if !p.retryable(statuses) {
 			break
 		}
 	}
 
-	currentStatus := p.status(currentState, s.currentAttempt)
+	currentStatus := p.status(currentState, true)
 
 	if p.plan.success != nil && currentStatus == status.Success && len(names) == 0 {
 		names = append(names, p.plan.success.Next(currentState, withCurrentAttempt(s.currentAttempt))...)
@@ -73,7 +83,7 @@ func (p *parallel) State(currentState status.Stater, options ...stepOption) stat
 	}
 	statuses := map[status.Type]int{}
 
-	statuses[p.status(currentState, s.currentAttempt)]++
+	statuses[p.status(currentState, s.runnable)]++
 
 	if p.plan.success != nil {
 		statuses[p.plan.success.State(currentState, withCurrentAttempt(s.currentAttempt))]++
@@ -103,13 +113,20 @@ func (p *parallel) State(currentState status.Stater, options ...stepOption) stat
 	return status.Running
 }
 
-func (p *parallel) status(currentState status.Stater, _ int) status.Type {
+func (p *parallel) status(currentState status.Stater, runnable bool) status.Type {
 	statuses := map[status.Type]int{}
 
 	for currentAttempt := 1; currentAttempt <= p.attempts; currentAttempt++ {
-		statuses = map[status.Type]int{}
-		for _, step := range p.steps {
-			statuses[step.State(currentState, withCurrentAttempt(currentAttempt))]++
+		statuses = p.attemptStatuses(currentState, currentAttempt, runnable)
+
+		if p.failFast && failedIn(statuses) {
+			if currentAttempt < p.attempts && p.retryable(statuses) {
+				continue
+			}
+			if statuses[status.Errored] > 0 {
+				return status.Errored
+			}
+			return status.Failed
 		}
 
 		if _, ok := statuses[status.Unstarted]; ok {
@@ -118,6 +135,9 @@ func (p *parallel) status(currentState status.Stater, _ int) status.Type {
 		if _, ok := statuses[status.Running]; ok {
 			break
 		}
+		if !p.retryable(statuses) {
+			break
+		}
 	}
 
 	if len(statuses) == 1 {
@@ -145,6 +165,18 @@ func (p *parallel) status(currentState status.Stater, _ int) status.Type {
 	return status.Running
 }
 
+func (p *parallel) failedFast(currentState status.Stater, currentAttempt int) bool {
+	return failedIn(p.attemptStatuses(currentState, currentAttempt, false))
+}
+
+func (p *parallel) attemptStatuses(currentState status.Stater, currentAttempt int, runnable bool) map[status.Type]int {
+	statuses := map[status.Type]int{}
+	for _, step := range p.steps {
+		statuses[resolved(step.State(currentState, withCurrentAttempt(currentAttempt), withRunnable(runnable)))]++
+	}
+	return statuses
+}
+
 func NewParallel(fun func(Planner) error, options ...configOption) (Step, error) {
 	plan := &parallel{newPlan()}
 
@@ -157,5 +189,12 @@ func NewParallel(fun func(Planner) error, options ...configOption) (Step, error)
 		return nil, err
 	}
 
+	if plan.validate {
+		err = validated(plan)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return plan, nil
 }
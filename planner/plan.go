@@ -18,6 +18,7 @@ type stepOption func(*step)
 
 type step struct {
 	currentAttempt int
+	runnable       bool
 }
 
 func withCurrentAttempt(currentAttempt int) func(*step) {
@@ -26,22 +27,10 @@ func withCurrentAttempt(currentAttempt int) func(*step) {
 	}
 }
 
-type Tree struct {
-	node     planType
-	task     Tasker
-	children []Tree
-}
-
-func (t Tree) Type() planType {
-	return t.node
-}
-
-func (t Tree) Children() []Tree {
-	return t.children
-}
-
-func (t Tree) Task() Tasker {
-	return t.task
+func withRunnable(runnable bool) func(*step) {
+	return func(s *step) {
+		s.runnable = runnable
+	}
 }
 
 type Step interface {
@@ -64,10 +53,20 @@ func WithMaxStepsInFlight(steps int) func(p *plan) {
 	}
 }
 
+func WithFailFast() func(p *plan) {
+	return func(p *plan) {
+		p.failFast = true
+	}
+}
+
 type Planner interface {
 	Task(Tasker) task
 	Parallel(func(Planner) error, ...configOption) error
 	Serial(func(Planner) error, ...configOption) error
+	Graph(func(Grapher) error, ...configOption) error
+	When(Predicate, func(Planner) error, ...configOption) error
+	Matrix(map[string][]string, func(Planner, map[string]string) error, ...configOption) error
+	Generate(Generator)
 	Success(func(Planner) error) error
 	Failure(func(Planner) error) error
 	Finally(func(Planner) error) error
@@ -84,6 +83,13 @@ type plan struct {
 
 	attempts int
 	maxSteps int
+	validate bool
+	failFast bool
+	backoff  Backoff
+	retryOn  map[status.Type]bool
+	include  []combination
+	exclude  []combination
+	locks    []string
 }
 
 var _ Planner = &plan{}
@@ -104,12 +110,20 @@ func (p *plan) tree(name planType) Tree {
 	if p.failure != nil {
 		nodes = append(nodes, p.failure.Tree())
 	}
+	if p.errored != nil {
+		nodes = append(nodes, p.errored.Tree())
+	}
 	if p.finally != nil {
 		nodes = append(nodes, p.finally.Tree())
 	}
 	return Tree{
 		node:     name,
 		children: nodes,
+		attempts: p.attempts,
+		maxSteps: p.maxSteps,
+		failFast: p.failFast,
+		backoff:  p.backoff,
+		locks:    p.locks,
 	}
 }
 
@@ -118,6 +132,22 @@ type Tasker interface {
 	Execute(io.Writer, io.Writer) (status.Type, error)
 }
 
+type Wrapper interface {
+	Unwrap() Tasker
+}
+
+func Unwrap(unit Tasker) []Tasker {
+	units := []Tasker{unit}
+	for {
+		wrapper, ok := unit.(Wrapper)
+		if !ok {
+			return units
+		}
+		unit = wrapper.Unwrap()
+		units = append(units, unit)
+	}
+}
+
 func (p *plan) Task(unit Tasker) task {
 	t := task{unit}
 	p.steps = append(p.steps, t)
@@ -143,31 +173,39 @@ func (p *plan) State(currentState status.Stater, options ...stepOption) status.T
 	for _, o := range options {
 		o(s)
 	}
-	return p.steps[0].State(currentState, withCurrentAttempt(s.currentAttempt))
+	return resolved(p.steps[0].State(currentState, withCurrentAttempt(s.currentAttempt), withRunnable(s.runnable)))
 }
 
 type success struct{ *plan }
 
 func (p *success) Tree() Tree {
-	return p.tree(Success)
+	tree := p.tree(Success)
+	tree.step = p
+	return tree
 }
 
 type failure struct{ *plan }
 
 func (p *failure) Tree() Tree {
-	return p.tree(Failure)
+	tree := p.tree(Failure)
+	tree.step = p
+	return tree
 }
 
 type errored struct{ *plan }
 
 func (p *errored) Tree() Tree {
-	return p.tree(Failure)
+	tree := p.tree(Error)
+	tree.step = p
+	return tree
 }
 
 type finally struct{ *plan }
 
 func (p *finally) Tree() Tree {
-	return p.tree(Finally)
+	tree := p.tree(Finally)
+	tree.step = p
+	return tree
 }
 
 func (p *plan) Success(fun func(Planner) error) error {
@@ -221,6 +259,12 @@ func (p *plan) Parallel(fun func(Planner) error, options ...configOption) error
 	if err != nil {
 		return fmt.Errorf("could not create parallel step: %s", err)
 	}
+	if plan.validate {
+		err = validated(plan)
+		if err != nil {
+			return fmt.Errorf("could not create parallel step: %s", err)
+		}
+	}
 	p.steps = append(p.steps, plan)
 	return nil
 }
@@ -236,6 +280,39 @@ func (p *plan) Serial(fun func(Planner) error, options ...configOption) error {
 	if err != nil {
 		return fmt.Errorf("could not create serial step: %s", err)
 	}
+	if plan.validate {
+		err = validated(plan)
+		if err != nil {
+			return fmt.Errorf("could not create serial step: %s", err)
+		}
+	}
+	p.steps = append(p.steps, plan)
+	return nil
+}
+
+func (p *plan) Graph(fun func(Grapher) error, options ...configOption) error {
+	plan, err := newGraph(fun, options...)
+	if err != nil {
+		return fmt.Errorf("could not create graph step: %s", err)
+	}
+	p.steps = append(p.steps, plan)
+	return nil
+}
+
+func (p *plan) When(predicate Predicate, fun func(Planner) error, options ...configOption) error {
+	plan := &when{
+		serial:    &serial{newPlan()},
+		predicate: predicate,
+	}
+
+	for _, o := range options {
+		o(plan.plan)
+	}
+
+	err := fun(plan.serial)
+	if err != nil {
+		return fmt.Errorf("could not create when step: %s", err)
+	}
 	p.steps = append(p.steps, plan)
 	return nil
 }
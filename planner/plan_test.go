@@ -30,7 +30,8 @@ func (m *matcher) Match(actual interface{}) (success bool, err error) {
 }
 
 type fakeState struct {
-	statuses map[string][]status.Type
+	statuses  map[string][]status.Type
+	decisions map[string]bool
 }
 
 func (f *fakeState) Get(task status.Identifier) []status.Type {
@@ -42,9 +43,32 @@ func (f *fakeState) Add(task status.Identifier, s status.Type) error {
 	return nil
 }
 
+func (f *fakeState) Watch() (<-chan status.Event, func()) {
+	return make(chan status.Event), func() {}
+}
+
+func (f *fakeState) Attempts(status.Identifier) []status.Attempt {
+	return nil
+}
+
+func (f *fakeState) AddError(status.Identifier, error) error {
+	return nil
+}
+
+func (f *fakeState) Recall(key string) (bool, bool) {
+	value, ok := f.decisions[key]
+	return value, ok
+}
+
+func (f *fakeState) Remember(key string, value bool) bool {
+	f.decisions[key] = value
+	return value
+}
+
 func newStatuses() *fakeState {
 	return &fakeState{
-		statuses: make(map[string][]status.Type),
+		statuses:  make(map[string][]status.Type),
+		decisions: make(map[string]bool),
 	}
 }
 
@@ -302,6 +326,22 @@ var _ = Describe("Planner", func() {
 			Expect(plan.Next(state)).To(EqualTasks([]task{}))
 			Expect(plan.State(state)).To(Equal(status.Running))
 		})
+
+		It("returns running if a single step is pending", func() {
+			plan, err := planner.NewParallel(func(plan planner.Planner) error {
+				plan.Task(task("A"))
+				plan.Task(task("B"))
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			state := newStatuses()
+			Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+			Expect(state.Add(task("B"), status.Pending)).ToNot(HaveOccurred())
+
+			Expect(plan.Next(state)).To(EqualTasks([]task{}))
+			Expect(plan.State(state)).To(Equal(status.Running))
+		})
 	})
 
 	Context("with a composed serial and parallel plan", func() {
@@ -0,0 +1,111 @@
+package planner
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/jtarchie/dothings/status"
+)
+
+type Snapshot struct {
+	Type     string     `json:"type"`
+	ID       string     `json:"id,omitempty"`
+	Children []Snapshot `json:"children,omitempty"`
+}
+
+func (t Tree) Snapshot() Snapshot {
+	snapshot := Snapshot{
+		Type: t.node.String(),
+		ID:   t.ID(),
+	}
+	for _, child := range t.children {
+		snapshot.Children = append(snapshot.Children, child.Snapshot())
+	}
+	return snapshot
+}
+
+type NodeState func(path []int) status.Type
+
+type replayedTask struct {
+	id string
+}
+
+func NewReplayedTask(id string) Tasker {
+	return replayedTask{id}
+}
+
+func (r replayedTask) ID() string {
+	return r.id
+}
+
+func (r replayedTask) Execute(io.Writer, io.Writer) (status.Type, error) {
+	return status.Errored, fmt.Errorf("task '%s' is a replay and cannot be executed", r.id)
+}
+
+type replayed struct {
+	node     planType
+	id       string
+	path     []int
+	children []*replayed
+	state    NodeState
+}
+
+var _ Step = &replayed{}
+
+func NewReplay(snapshot Snapshot, state NodeState) (Step, error) {
+	return newReplayed(snapshot, []int{}, state)
+}
+
+func newReplayed(snapshot Snapshot, path []int, state NodeState) (*replayed, error) {
+	node, ok := parsePlanType(snapshot.Type)
+	if !ok {
+		return nil, fmt.Errorf("could not replay step: unknown type '%s'", snapshot.Type)
+	}
+
+	step := &replayed{
+		node:  node,
+		id:    snapshot.ID,
+		path:  path,
+		state: state,
+	}
+	for i, child := range snapshot.Children {
+		childPath := append(path[:len(path):len(path)], i)
+		replay, err := newReplayed(child, childPath, state)
+		if err != nil {
+			return nil, err
+		}
+		step.children = append(step.children, replay)
+	}
+	return step, nil
+}
+
+func (r *replayed) Next(status.Stater, ...stepOption) Tasks {
+	return Tasks{}
+}
+
+func (r *replayed) State(currentState status.Stater, _ ...stepOption) status.Type {
+	if r.node == Task {
+		if states := currentState.Get(replayedTask{r.id}); len(states) > 0 {
+			return states[len(states)-1]
+		}
+		return status.Unstarted
+	}
+	return r.state(r.path)
+}
+
+func (r *replayed) Tree() Tree {
+	tree := Tree{
+		node:     r.node,
+		attempts: 1,
+		step:     r,
+	}
+	if r.node == Task {
+		tree.task = replayedTask{r.id}
+	} else {
+		tree.id = r.id
+	}
+	for _, child := range r.children {
+		tree.children = append(tree.children, child.Tree())
+	}
+	return tree
+}
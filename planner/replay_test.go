@@ -0,0 +1,72 @@
+package planner_test
+
+import (
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Replay", func() {
+	It("snapshots the shape of a tree", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			err := plan.Parallel(func(plan planner.Planner) error {
+				plan.Task(task("B"))
+				return nil
+			})
+			if err != nil {
+				return err
+			}
+			return plan.Finally(func(plan planner.Planner) error {
+				plan.Task(task("C"))
+				return nil
+			})
+		})
+
+		Expect(plan.Tree().Snapshot()).To(Equal(planner.Snapshot{
+			Type: "serial",
+			Children: []planner.Snapshot{
+				{Type: "task", ID: "A"},
+				{Type: "parallel", Children: []planner.Snapshot{{Type: "task", ID: "B"}}},
+				{Type: "finally", Children: []planner.Snapshot{{Type: "task", ID: "C"}}},
+			},
+		}))
+	})
+
+	It("rebuilds a tree with recorded states that never schedules tasks", func() {
+		snapshot := planner.Snapshot{
+			Type: "serial",
+			Children: []planner.Snapshot{
+				{Type: "task", ID: "A"},
+				{Type: "conditional", Children: []planner.Snapshot{{Type: "task", ID: "B"}}},
+			},
+		}
+		plan, err := planner.NewReplay(snapshot, func(path []int) status.Type {
+			if len(path) == 0 {
+				return status.Failed
+			}
+			return status.Skipped
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Unstarted)).To(Succeed())
+		Expect(state.Add(task("A"), status.Running)).To(Succeed())
+
+		Expect(plan.Next(state)).To(BeEmpty())
+		Expect(plan.State(state)).To(Equal(status.Failed))
+		Expect(plan.Tree().Snapshot()).To(Equal(snapshot))
+
+		children := plan.Tree().Children()
+		Expect(children[0].Task().ID()).To(Equal("A"))
+		Expect(children[0].State(state)).To(Equal(status.Running))
+		Expect(children[1].State(state)).To(Equal(status.Skipped))
+		Expect(children[1].Children()[0].State(state)).To(Equal(status.Unstarted))
+	})
+
+	It("errors on unknown step types", func() {
+		_, err := planner.NewReplay(planner.Snapshot{Type: "unknown"}, nil)
+		Expect(err).To(MatchError("could not replay step: unknown type 'unknown'"))
+	})
+})
@@ -0,0 +1,58 @@
+package planner
+
+import "github.com/jtarchie/dothings/status"
+
+func Rerun(step Step, currentState status.Stater) Tasks {
+	root := step.Tree()
+	tasks, seen := Tasks{}, map[string]bool{}
+	add := func(task Tasker) {
+		if !seen[task.ID()] {
+			seen[task.ID()] = true
+			tasks = append(tasks, task)
+		}
+	}
+
+	failed := Tasks{}
+	_ = root.Walk(func(node Tree, _ []Tree) error {
+		if node.Type() != Task {
+			return nil
+		}
+
+		states := currentState.Get(node.Task())
+		if len(states) == 0 {
+			return nil
+		}
+		switch states[len(states)-1] {
+		case status.Failed, status.Errored:
+			failed = append(failed, node.Task())
+		}
+		return nil
+	})
+
+	for _, task := range failed {
+		add(task)
+
+		path, _ := root.Path(task.ID())
+		for _, parent := range path {
+			for _, child := range parent.Children() {
+				switch child.Type() {
+				case Failure, Error, Finally:
+				default:
+					continue
+				}
+				if _, inHook := child.Path(task.ID()); inHook {
+					continue
+				}
+
+				_ = child.Walk(func(node Tree, _ []Tree) error {
+					if node.Type() == Task && len(currentState.Get(node.Task())) > 0 {
+						add(node.Task())
+					}
+					return nil
+				})
+			}
+		}
+	}
+
+	return tasks
+}
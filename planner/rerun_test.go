@@ -0,0 +1,70 @@
+package planner_test
+
+import (
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rerun", func() {
+	var plan planner.Step
+
+	BeforeEach(func() {
+		var err error
+		plan, err = planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			err := plan.Parallel(func(plan planner.Planner) error {
+				plan.Task(task("B"))
+				plan.Task(task("C"))
+				return plan.Failure(func(plan planner.Planner) error {
+					plan.Task(task("notify"))
+					return nil
+				})
+			})
+			if err != nil {
+				return err
+			}
+			plan.Task(task("D"))
+			return plan.Finally(func(plan planner.Planner) error {
+				plan.Task(task("cleanup"))
+				return nil
+			})
+		})
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("resets failed tasks and the hooks that ran because of them", func() {
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+		Expect(state.Add(task("B"), status.Success)).ToNot(HaveOccurred())
+		Expect(state.Add(task("C"), status.Errored)).ToNot(HaveOccurred())
+		Expect(state.Add(task("notify"), status.Success)).ToNot(HaveOccurred())
+		Expect(state.Add(task("cleanup"), status.Success)).ToNot(HaveOccurred())
+
+		Expect(planner.Rerun(plan, state)).To(EqualTasks([]task{"C", "cleanup", "notify"}))
+	})
+
+	It("keeps tasks that succeeded on a later attempt", func() {
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Failed)).ToNot(HaveOccurred())
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+		Expect(state.Add(task("B"), status.Success)).ToNot(HaveOccurred())
+		Expect(state.Add(task("C"), status.Success)).ToNot(HaveOccurred())
+		Expect(state.Add(task("D"), status.Success)).ToNot(HaveOccurred())
+		Expect(state.Add(task("cleanup"), status.Success)).ToNot(HaveOccurred())
+
+		Expect(planner.Rerun(plan, state)).To(BeEmpty())
+	})
+
+	It("resets a failed hook without resetting its siblings", func() {
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+		Expect(state.Add(task("B"), status.Success)).ToNot(HaveOccurred())
+		Expect(state.Add(task("C"), status.Success)).ToNot(HaveOccurred())
+		Expect(state.Add(task("D"), status.Success)).ToNot(HaveOccurred())
+		Expect(state.Add(task("cleanup"), status.Failed)).ToNot(HaveOccurred())
+
+		Expect(planner.Rerun(plan, state)).To(EqualTasks([]task{"cleanup"}))
+	})
+})
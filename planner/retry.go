@@ -0,0 +1,115 @@
+package planner
+
+import (
+	"math/rand"
+	"time"
+
+	"github.com/jtarchie/dothings/status"
+)
+
+type Backoff func(attempt int) time.Duration
+
+func FixedBackoff(delay time.Duration) Backoff {
+	return func(int) time.Duration {
+		return delay
+	}
+}
+
+func ExponentialBackoff(initial time.Duration, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		delay := initial
+		for i := 1; i < attempt; i++ {
+			delay *= 2
+			if max > 0 && delay >= max {
+				return max
+			}
+		}
+		return delay
+	}
+}
+
+func WithJitter(backoff Backoff, fraction float64) Backoff {
+	return func(attempt int) time.Duration {
+		delay := backoff(attempt)
+		jitter := time.Duration(float64(delay) * fraction * (2*rand.Float64() - 1))
+		return delay + jitter
+	}
+}
+
+func WithBackoff(backoff Backoff) func(p *plan) {
+	return func(p *plan) {
+		p.backoff = backoff
+	}
+}
+
+func WithRetryOn(statuses ...status.Type) func(p *plan) {
+	return func(p *plan) {
+		p.retryOn = map[status.Type]bool{}
+		for _, s := range statuses {
+			p.retryOn[s] = true
+		}
+	}
+}
+
+func (p *plan) retries(s status.Type) bool {
+	if p.retryOn == nil {
+		return true
+	}
+	return p.retryOn[s]
+}
+
+func (p *plan) retryable(statuses map[status.Type]int) bool {
+	if statuses[status.Errored] > 0 {
+		return p.retries(status.Errored)
+	}
+	if statuses[status.Failed] > 0 {
+		return p.retries(status.Failed)
+	}
+	return false
+}
+
+func failedIn(statuses map[status.Type]int) bool {
+	return statuses[status.Failed] > 0 || statuses[status.Errored] > 0
+}
+
+type FinishedAt func(unit Tasker, attempt int) (time.Time, bool)
+
+func RetryDelay(step Step, unit Tasker, attempt int, finishedAt FinishedAt, now time.Time) time.Duration {
+	if attempt < 2 {
+		return 0
+	}
+
+	path, ok := step.Tree().Path(unit.ID())
+	if !ok {
+		return 0
+	}
+
+	for i := len(path) - 2; i >= 0; i-- {
+		node := path[i]
+		if node.backoff == nil || node.attempts < attempt {
+			continue
+		}
+
+		var endedAt time.Time
+		_ = node.Walk(func(node Tree, _ []Tree) error {
+			if node.Type() != Task {
+				return nil
+			}
+			if finished, ok := finishedAt(node.Task(), attempt-1); ok && finished.After(endedAt) {
+				endedAt = finished
+			}
+			return nil
+		})
+
+		if endedAt.IsZero() {
+			return 0
+		}
+
+		if remaining := node.backoff(attempt-1) - now.Sub(endedAt); remaining > 0 {
+			return remaining
+		}
+		return 0
+	}
+
+	return 0
+}
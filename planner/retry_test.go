@@ -0,0 +1,141 @@
+package planner_test
+
+import (
+	"time"
+
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Retry", func() {
+	Context("backoffs", func() {
+		It("returns a fixed delay", func() {
+			backoff := planner.FixedBackoff(time.Second)
+			Expect(backoff(1)).To(Equal(time.Second))
+			Expect(backoff(5)).To(Equal(time.Second))
+		})
+
+		It("doubles the delay up to a max", func() {
+			backoff := planner.ExponentialBackoff(time.Second, 5*time.Second)
+			Expect(backoff(1)).To(Equal(time.Second))
+			Expect(backoff(2)).To(Equal(2 * time.Second))
+			Expect(backoff(3)).To(Equal(4 * time.Second))
+			Expect(backoff(4)).To(Equal(5 * time.Second))
+		})
+
+		It("adds jitter around the delay", func() {
+			backoff := planner.WithJitter(planner.FixedBackoff(time.Second), 0.5)
+			for i := 0; i < 100; i++ {
+				Expect(backoff(1)).To(BeNumerically("~", time.Second, 500*time.Millisecond))
+			}
+		})
+	})
+
+	Context("retrying on specific statuses", func() {
+		for _, kind := range []string{"serial", "parallel", "graph"} {
+			kind := kind
+
+			build := func(retryOn status.Type) planner.Step {
+				var (
+					plan planner.Step
+					err  error
+				)
+				switch kind {
+				case "serial":
+					plan, err = planner.NewSerial(func(plan planner.Planner) error {
+						plan.Task(task("A"))
+						return nil
+					}, planner.WithAttempts(2), planner.WithRetryOn(retryOn))
+				case "parallel":
+					plan, err = planner.NewParallel(func(plan planner.Planner) error {
+						plan.Task(task("A"))
+						return nil
+					}, planner.WithAttempts(2), planner.WithRetryOn(retryOn))
+				case "graph":
+					plan, err = planner.NewGraph(func(graph planner.Grapher) error {
+						graph.Task(task("A"))
+						return nil
+					}, planner.WithAttempts(2), planner.WithRetryOn(retryOn))
+				}
+				Expect(err).NotTo(HaveOccurred())
+				return plan
+			}
+
+			It("retries "+kind+" steps on the given status", func() {
+				plan := build(status.Errored)
+
+				state := newStatuses()
+				Expect(state.Add(task("A"), status.Errored)).ToNot(HaveOccurred())
+				Expect(plan.Next(state)).To(EqualTasks([]task{"A"}))
+				Expect(plan.State(state)).To(Equal(status.Running))
+			})
+
+			It("does not retry "+kind+" steps on other statuses", func() {
+				plan := build(status.Errored)
+
+				state := newStatuses()
+				Expect(state.Add(task("A"), status.Failed)).ToNot(HaveOccurred())
+				Expect(plan.Next(state)).To(EqualTasks([]task{}))
+				Expect(plan.State(state)).To(Equal(status.Failed))
+
+				plan = build(status.Failed)
+
+				state = newStatuses()
+				Expect(state.Add(task("A"), status.Errored)).ToNot(HaveOccurred())
+				Expect(plan.Next(state)).To(EqualTasks([]task{}))
+				Expect(plan.State(state)).To(Equal(status.Errored))
+			})
+		}
+	})
+
+	Context("the delay before an attempt", func() {
+		var (
+			plan     planner.Step
+			finished map[string][]time.Time
+			now      time.Time
+		)
+
+		finishedAt := func(unit planner.Tasker, attempt int) (time.Time, bool) {
+			if times := finished[unit.ID()]; len(times) >= attempt {
+				return times[attempt-1], true
+			}
+			return time.Time{}, false
+		}
+
+		BeforeEach(func() {
+			now = time.Now()
+			finished = map[string][]time.Time{}
+			plan, _ = planner.NewParallel(func(plan planner.Planner) error {
+				plan.Task(task("C"))
+				return plan.Serial(func(plan planner.Planner) error {
+					plan.Task(task("A"))
+					plan.Task(task("B"))
+					return nil
+				}, planner.WithAttempts(3), planner.WithBackoff(planner.ExponentialBackoff(time.Minute, 0)))
+			})
+		})
+
+		It("has no delay for the first attempt", func() {
+			Expect(planner.RetryDelay(plan, task("A"), 1, finishedAt, now)).To(Equal(time.Duration(0)))
+		})
+
+		It("has no delay for tasks without a backoff", func() {
+			finished["C"] = []time.Time{now}
+			Expect(planner.RetryDelay(plan, task("C"), 2, finishedAt, now)).To(Equal(time.Duration(0)))
+		})
+
+		It("waits from the end of the previous attempt", func() {
+			finished["A"] = []time.Time{now.Add(-50 * time.Second)}
+			finished["B"] = []time.Time{now.Add(-10 * time.Second)}
+			Expect(planner.RetryDelay(plan, task("A"), 2, finishedAt, now)).To(Equal(50 * time.Second))
+
+			finished["A"] = append(finished["A"], now.Add(time.Minute))
+			Expect(planner.RetryDelay(plan, task("B"), 2, finishedAt, now.Add(time.Minute))).To(Equal(time.Duration(0)))
+
+			finished["B"] = append(finished["B"], now.Add(2*time.Minute))
+			Expect(planner.RetryDelay(plan, task("A"), 3, finishedAt, now.Add(2*time.Minute))).To(Equal(2 * time.Minute))
+		})
+	})
+})
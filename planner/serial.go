@@ -9,7 +9,9 @@ type serial struct {
 var _ Step = &serial{}
 
 func (p *serial) Tree() Tree {
-	return p.tree(Serial)
+	tree := p.tree(Serial)
+	tree.step = p
+	return tree
 }
 
 func (p *serial) Next(currentState status.Stater, options ...stepOption) Tasks {
@@ -20,15 +22,16 @@ func (p *serial) Next(currentState status.Stater, options ...stepOption) Tasks {
 		o(s)
 	}
 
-	names, failed := Tasks{}, false
+	names, failed, retry := Tasks{}, false, true
 	for currentAttempt := 1; currentAttempt <= p.attempts; currentAttempt++ {
 		for _, step := range p.steps {
-			switch step.State(currentState, withCurrentAttempt(currentAttempt)) {
+			switch state := resolved(step.State(currentState, withCurrentAttempt(currentAttempt), withRunnable(true))); state {
 			case status.Success:
 				continue
 			case status.Failed, status.Errored:
 				names = Tasks{}
 				failed = true
+				retry = p.retries(state)
 				goto outOfStepLoop
 			default:
 				n := step.Next(currentState, withCurrentAttempt(currentAttempt))
@@ -43,12 +46,15 @@ func (p *serial) Next(currentState status.Stater, options ...stepOption) Tasks {
 		if len(names) == 0 && !failed {
 			break
 		}
+		if failed && !retry {
+			break
+		}
 		if len(names) > 0 {
 			break
 		}
 	}
 
-	currentStatus := p.status(currentState, s.currentAttempt)
+	currentStatus := p.status(currentState, true)
 
 	if p.plan.success != nil && currentStatus == status.Success {
 		if n := p.plan.success.Next(currentState, withCurrentAttempt(s.currentAttempt)); len(n) > 0 {
@@ -89,7 +95,7 @@ func (p *serial) State(currentState status.Stater, options ...stepOption) status
 	}
 	statuses := map[status.Type]int{}
 
-	statuses[p.status(currentState, s.currentAttempt)]++
+	statuses[p.status(currentState, s.runnable)]++
 
 	if p.plan.success != nil {
 		statuses[p.plan.success.State(currentState, withCurrentAttempt(s.currentAttempt))]++
@@ -120,18 +126,24 @@ func (p *serial) State(currentState status.Stater, options ...stepOption) status
 	return status.Running
 }
 
-func (p *serial) status(currentState status.Stater, _ int) status.Type {
+func (p *serial) status(currentState status.Stater, runnable bool) status.Type {
 	statuses := map[status.Type]int{}
 
 	for currentAttempt := 1; currentAttempt <= p.attempts; currentAttempt++ {
 		statuses = map[status.Type]int{}
+		reached := runnable
 		for _, step := range p.steps {
-			statuses[step.State(currentState, withCurrentAttempt(currentAttempt))]++
+			state := resolved(step.State(currentState, withCurrentAttempt(currentAttempt), withRunnable(reached)))
+			statuses[state]++
+			reached = reached && state == status.Success
 		}
-		if len(statuses) == 1 {
-			for status := range statuses {
-				return status
+		if !p.retryable(statuses) || currentAttempt == p.attempts {
+			if len(statuses) == 1 {
+				for status := range statuses {
+					return status
+				}
 			}
+			break
 		}
 	}
 
@@ -158,5 +170,12 @@ func NewSerial(fun func(Planner) error, options ...configOption) (Step, error) {
 		return nil, err
 	}
 
+	if plan.validate {
+		err = validated(plan)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return plan, nil
 }
@@ -10,8 +10,11 @@ var _ Step = task{}
 
 func (t task) Tree() Tree {
 	return Tree{
-		node: Task,
-		task: t.unitOfWork,
+		node:     Task,
+		task:     t.unitOfWork,
+		attempts: 1,
+		step:     t,
+		locks:    taskLocks(t.unitOfWork),
 	}
 }
 
@@ -0,0 +1,153 @@
+package planner
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/jtarchie/dothings/status"
+)
+
+var SkipChildren = errors.New("skip children")
+
+type Tree struct {
+	node      planType
+	task      Tasker
+	children  []Tree
+	attempts  int
+	maxSteps  int
+	id        string
+	dependsOn []string
+	step      Step
+	failFast  bool
+	backoff   Backoff
+	locks     []string
+}
+
+func (t Tree) Type() planType {
+	return t.node
+}
+
+func (t Tree) Children() []Tree {
+	return t.children
+}
+
+func (t Tree) Task() Tasker {
+	return t.task
+}
+
+func (t Tree) ID() string {
+	if t.node == Task {
+		return t.task.ID()
+	}
+	return t.id
+}
+
+func (t Tree) DependsOn() []string {
+	return t.dependsOn
+}
+
+func (t Tree) State(currentState status.Stater) status.Type {
+	if t.step == nil {
+		return status.Unstarted
+	}
+	return t.step.State(currentState)
+}
+
+func (t Tree) Attempts() int {
+	return t.attempts
+}
+
+func (t Tree) MaxStepsInFlight() int {
+	return t.maxSteps
+}
+
+func (t Tree) Locks() []string {
+	return t.locks
+}
+
+func (t Tree) FailFast() bool {
+	return t.failFast
+}
+
+func (t Tree) Attempted(currentState status.Stater) int {
+	if t.node == Task {
+		return len(currentState.Get(t.task))
+	}
+
+	attempted := 0
+	for _, child := range t.children {
+		if n := child.Attempted(currentState); n > attempted {
+			attempted = n
+		}
+	}
+	return attempted
+}
+
+func (t Tree) Walk(fun func(node Tree, parents []Tree) error) error {
+	return t.walk(fun, []Tree{})
+}
+
+func (t Tree) walk(fun func(Tree, []Tree) error, parents []Tree) error {
+	err := fun(t, parents)
+	if err == SkipChildren {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	parents = append(parents[:len(parents):len(parents)], t)
+	for _, child := range t.children {
+		err := child.walk(fun, parents)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t Tree) Find(matcher func(Tree) bool) (Tree, bool) {
+	if matcher(t) {
+		return t, true
+	}
+
+	for _, child := range t.children {
+		if node, ok := child.Find(matcher); ok {
+			return node, true
+		}
+	}
+	return Tree{}, false
+}
+
+func (t Tree) Path(id string) ([]Tree, bool) {
+	if t.node == Task && t.task.ID() == id {
+		return []Tree{t}, true
+	}
+
+	for _, child := range t.children {
+		if path, ok := child.Path(id); ok {
+			return append([]Tree{t}, path...), true
+		}
+	}
+	return nil, false
+}
+
+func Branch(step Step, id string) string {
+	branch, _ := step.Tree().branch(id, "")
+	return branch
+}
+
+func (t Tree) branch(id string, prefix string) (string, bool) {
+	for i, child := range t.children {
+		if _, ok := child.Path(id); !ok {
+			continue
+		}
+
+		key := fmt.Sprintf("%s/%d", prefix, i)
+		if t.node == Parallel || t.node == Graph {
+			return key, true
+		}
+		return child.branch(id, key)
+	}
+	return "", false
+}
@@ -81,4 +81,96 @@ var _ = Describe("Tree", func() {
 		Expect(tree.Children()[2].Type()).To(Equal(Failure))
 		Expect(tree.Children()[3].Type()).To(Equal(Finally))
 	})
+
+	It("reports the error hook with its own type", func() {
+		plan, err := NewSerial(func(plan Planner) error {
+			plan.Task(tasks.NewEcho("a", status.Success))
+			return plan.Error(func(errored Planner) error {
+				errored.Task(tasks.NewEcho("b", status.Success))
+				return nil
+			})
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		tree := plan.Tree()
+		Expect(tree.Children()).To(HaveLen(2))
+		Expect(tree.Children()[1].Type()).To(Equal(Error))
+		Expect(tree.Children()[1].Type().String()).To(Equal("error"))
+	})
+
+	It("exposes the configured options", func() {
+		plan, err := NewSerial(func(plan Planner) error {
+			return plan.Parallel(func(parallel Planner) error {
+				parallel.Task(tasks.NewEcho("a", status.Success))
+				return nil
+			}, WithAttempts(3), WithMaxStepsInFlight(2))
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		tree := plan.Tree()
+		Expect(tree.Attempts()).To(Equal(1))
+		Expect(tree.MaxStepsInFlight()).To(Equal(0))
+		Expect(tree.Children()[0].Attempts()).To(Equal(3))
+		Expect(tree.Children()[0].MaxStepsInFlight()).To(Equal(2))
+		Expect(tree.Children()[0].Children()[0].Attempts()).To(Equal(1))
+	})
+
+	It("counts the attempts of a node", func() {
+		state := status.NewStatuses()
+		a := tasks.NewEcho("a", status.Success)
+		for _, s := range []status.Type{status.Unstarted, status.Running, status.Failed, status.Unstarted} {
+			Expect(state.Add(a, s)).NotTo(HaveOccurred())
+		}
+
+		tree := plan.Tree()
+		Expect(tree.Attempted(state)).To(Equal(2))
+		Expect(tree.Children()[0].Children()[1].Attempted(state)).To(Equal(0))
+	})
+
+	It("walks every node with its parents", func() {
+		visited := []string{}
+		err := plan.Tree().Walk(func(node Tree, parents []Tree) error {
+			if node.Type() == Task {
+				visited = append(visited, node.Task().ID())
+				Expect(parents[0].Type()).To(Equal(Serial))
+			}
+			if node.Type() == Failure {
+				return SkipChildren
+			}
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(visited).To(Equal([]string{"a", "a1", "b", "d"}))
+	})
+
+	It("finds a node", func() {
+		node, ok := plan.Tree().Find(func(node Tree) bool {
+			return node.Type() == Try
+		})
+		Expect(ok).To(BeTrue())
+		Expect(node.Children()[0].Task().ID()).To(Equal("a"))
+
+		_, ok = plan.Tree().Find(func(node Tree) bool {
+			return node.Type() == Error
+		})
+		Expect(ok).To(BeFalse())
+	})
+
+	It("returns the path to a task", func() {
+		path, ok := plan.Tree().Path("a")
+		Expect(ok).To(BeTrue())
+		Expect(path).To(HaveLen(4))
+		Expect(path[0].Type()).To(Equal(Serial))
+		Expect(path[1].Type()).To(Equal(Parallel))
+		Expect(path[2].Type()).To(Equal(Try))
+		Expect(path[3].Task().ID()).To(Equal("a"))
+
+		_, ok = plan.Tree().Path("unknown")
+		Expect(ok).To(BeFalse())
+	})
+	It("names the parallel branch a task belongs to", func() {
+		Expect(Branch(plan, "a")).To(Equal("/0/0"))
+		Expect(Branch(plan, "a1")).To(Equal("/0/1"))
+		Expect(Branch(plan, "unknown")).To(Equal(""))
+	})
 })
@@ -7,7 +7,9 @@ type try struct{ *plan }
 var _ Step = &try{}
 
 func (p *try) Tree() Tree {
-	return p.tree(Try)
+	tree := p.tree(Try)
+	tree.step = p
+	return tree
 }
 
 func (p *try) State(currentState status.Stater, options ...stepOption) status.Type {
@@ -17,7 +19,7 @@ func (p *try) State(currentState status.Stater, options ...stepOption) status.Ty
 	for _, o := range options {
 		o(s)
 	}
-	state := p.steps[0].State(currentState, withCurrentAttempt(s.currentAttempt))
+	state := p.steps[0].State(currentState, withCurrentAttempt(s.currentAttempt), withRunnable(s.runnable))
 	if state == status.Failed {
 		return status.Success
 	}
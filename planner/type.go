@@ -10,6 +10,10 @@ const (
 	Success
 	Failure
 	Finally
+	Error
+	Graph
+	Conditional
+	Generated
 )
 
 func (p planType) String() string {
@@ -28,6 +32,42 @@ func (p planType) String() string {
 		return "failure"
 	case Finally:
 		return "finally"
+	case Error:
+		return "error"
+	case Graph:
+		return "graph"
+	case Conditional:
+		return "conditional"
+	case Generated:
+		return "generated"
 	}
 	return ""
 }
+
+func parsePlanType(name string) (planType, bool) {
+	switch name {
+	case "parallel":
+		return Parallel, true
+	case "serial":
+		return Serial, true
+	case "task":
+		return Task, true
+	case "try":
+		return Try, true
+	case "success":
+		return Success, true
+	case "failure":
+		return Failure, true
+	case "finally":
+		return Finally, true
+	case "error":
+		return Error, true
+	case "graph":
+		return Graph, true
+	case "conditional":
+		return Conditional, true
+	case "generated":
+		return Generated, true
+	}
+	return 0, false
+}
@@ -0,0 +1,98 @@
+package planner
+
+import (
+	"fmt"
+	"strings"
+)
+
+func WithValidation() func(p *plan) {
+	return func(p *plan) {
+		p.validate = true
+	}
+}
+
+func Validate(step Step) []error {
+	v := &validator{
+		ids: map[string]string{},
+	}
+	tree := step.Tree()
+	v.check(tree, tree.Type().String())
+	return v.errs
+}
+
+type validator struct {
+	ids  map[string]string
+	errs []error
+}
+
+func (v *validator) check(tree Tree, path string) {
+	if tree.Type() == Task {
+		id := tree.Task().ID()
+		if other, ok := v.ids[id]; ok {
+			v.errs = append(v.errs, fmt.Errorf("%s: duplicate task ID '%s' also used at %s", path, id, other))
+		} else {
+			v.ids[id] = path
+		}
+		return
+	}
+
+	if tree.Attempts() < 1 {
+		v.errs = append(v.errs, fmt.Errorf("%s: attempts must be at least 1, got %d", path, tree.Attempts()))
+	}
+	if tree.MaxStepsInFlight() < 0 {
+		v.errs = append(v.errs, fmt.Errorf("%s: max steps in flight cannot be negative, got %d", path, tree.MaxStepsInFlight()))
+	}
+
+	steps, hooks := 0, []string{}
+	for index, child := range tree.Children() {
+		childPath := fmt.Sprintf("%s/%s[%d]", path, child.Type(), index)
+		if child.Type() == Task {
+			childPath = fmt.Sprintf("%s/task '%s'", path, child.Task().ID())
+		}
+
+		if isHook(child.Type()) {
+			hooks = append(hooks, child.Type().String())
+		} else {
+			steps++
+		}
+		v.check(child, childPath)
+	}
+
+	if steps == 0 {
+		v.errs = append(v.errs, fmt.Errorf("%s: has no steps", path))
+	}
+
+	if !runsAllSteps(tree.Type()) {
+		if steps > 1 {
+			v.errs = append(v.errs, fmt.Errorf("%s: only the first of %d steps is ever run", path, steps))
+		}
+		if len(hooks) > 0 {
+			v.errs = append(v.errs, fmt.Errorf("%s: %s hooks can never be reached", path, strings.Join(hooks, ", ")))
+		}
+	}
+}
+
+func isHook(node planType) bool {
+	switch node {
+	case Success, Failure, Error, Finally:
+		return true
+	}
+	return false
+}
+
+func runsAllSteps(node planType) bool {
+	return node == Serial || node == Parallel || node == Graph || node == Conditional || node == Generated
+}
+
+func validated(step Step) error {
+	errs := Validate(step)
+	if len(errs) == 0 {
+		return nil
+	}
+
+	messages := []string{}
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return fmt.Errorf("invalid plan: %s", strings.Join(messages, "; "))
+}
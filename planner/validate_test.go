@@ -0,0 +1,131 @@
+package planner_test
+
+import (
+	"github.com/jtarchie/dothings/planner"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+func errorMessages(errs []error) []string {
+	messages := []string{}
+	for _, err := range errs {
+		messages = append(messages, err.Error())
+	}
+	return messages
+}
+
+var _ = Describe("Validate", func() {
+	It("returns no errors for a valid plan", func() {
+		plan, err := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			err := plan.Parallel(func(plan planner.Planner) error {
+				plan.Task(task("B"))
+				plan.Task(task("C"))
+				return nil
+			}, planner.WithAttempts(2), planner.WithMaxStepsInFlight(1))
+			Expect(err).NotTo(HaveOccurred())
+
+			return plan.Finally(func(plan planner.Planner) error {
+				plan.Task(task("D"))
+				return nil
+			})
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(planner.Validate(plan)).To(BeEmpty())
+	})
+
+	It("reports empty groups", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			_ = plan.Parallel(func(plan planner.Planner) error {
+				return nil
+			})
+			return plan.Try(func(plan planner.Planner) error {
+				return nil
+			})
+		})
+
+		Expect(errorMessages(planner.Validate(plan))).To(ConsistOf(
+			"serial/parallel[0]: has no steps",
+			"serial/try[1]: has no steps",
+		))
+	})
+
+	It("reports duplicate task IDs", func() {
+		plan, _ := planner.NewParallel(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			return plan.Serial(func(plan planner.Planner) error {
+				plan.Task(task("A"))
+				return nil
+			})
+		})
+
+		Expect(errorMessages(planner.Validate(plan))).To(ConsistOf(
+			"parallel/serial[1]/task 'A': duplicate task ID 'A' also used at parallel/task 'A'",
+		))
+	})
+
+	It("reports hooks and steps that can never be reached", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			return plan.Try(func(plan planner.Planner) error {
+				plan.Task(task("A"))
+				plan.Task(task("B"))
+				return plan.Success(func(plan planner.Planner) error {
+					plan.Task(task("C"))
+					return nil
+				})
+			})
+		})
+
+		Expect(errorMessages(planner.Validate(plan))).To(ConsistOf(
+			"serial/try[0]: only the first of 2 steps is ever run",
+			"serial/try[0]: success hooks can never be reached",
+		))
+	})
+
+	It("reports invalid options", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			return nil
+		}, planner.WithAttempts(0), planner.WithMaxStepsInFlight(-1))
+
+		Expect(errorMessages(planner.Validate(plan))).To(ConsistOf(
+			"serial: attempts must be at least 1, got 0",
+			"serial: max steps in flight cannot be negative, got -1",
+		))
+	})
+
+	When("validation is enabled at construction", func() {
+		It("returns an error for an invalid plan", func() {
+			_, err := planner.NewSerial(func(plan planner.Planner) error {
+				return nil
+			}, planner.WithValidation())
+			Expect(err).To(MatchError("invalid plan: serial: has no steps"))
+
+			_, err = planner.NewParallel(func(plan planner.Planner) error {
+				plan.Task(task("A"))
+				plan.Task(task("A"))
+				return nil
+			}, planner.WithValidation())
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("validates nested groups", func() {
+			_, err := planner.NewSerial(func(plan planner.Planner) error {
+				return plan.Parallel(func(plan planner.Planner) error {
+					return nil
+				}, planner.WithValidation())
+			})
+			Expect(err).To(MatchError("could not create parallel step: invalid plan: parallel: has no steps"))
+		})
+
+		It("returns the plan when it is valid", func() {
+			plan, err := planner.NewSerial(func(plan planner.Planner) error {
+				plan.Task(task("A"))
+				return nil
+			}, planner.WithValidation())
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plan).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,71 @@
+package planner
+
+import (
+	"fmt"
+
+	"github.com/jtarchie/dothings/status"
+)
+
+type Predicate func(status.Stater) bool
+
+type when struct {
+	*serial
+	predicate Predicate
+}
+
+var _ Step = &when{}
+
+func (p *when) Tree() Tree {
+	tree := p.tree(Conditional)
+	tree.step = p
+	return tree
+}
+
+func (p *when) Next(currentState status.Stater, options ...stepOption) Tasks {
+	if !p.holds(currentState, append(options, withRunnable(true))...) {
+		return Tasks{}
+	}
+	return p.serial.Next(currentState, options...)
+}
+
+func (p *when) State(currentState status.Stater, options ...stepOption) status.Type {
+	if !p.holds(currentState, options...) {
+		return status.Skipped
+	}
+	return p.serial.State(currentState, options...)
+}
+
+func (p *when) holds(currentState status.Stater, options ...stepOption) bool {
+	s := &step{
+		currentAttempt: 1,
+	}
+	for _, o := range options {
+		o(s)
+	}
+
+	memo, ok := currentState.(status.Memo)
+	if !ok {
+		return p.predicate(currentState)
+	}
+
+	key := fmt.Sprintf("when %p attempt %d", p, s.currentAttempt)
+	if held, ok := memo.Recall(key); ok {
+		return held
+	}
+
+	held := p.predicate(currentState)
+	if s.runnable {
+		return memo.Remember(key, held)
+	}
+	return held
+}
+
+func resolved(s status.Type) status.Type {
+	switch s {
+	case status.Skipped:
+		return status.Success
+	case status.Pending:
+		return status.Running
+	}
+	return s
+}
@@ -0,0 +1,177 @@
+package planner_test
+
+import (
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("When", func() {
+	succeeded := func(id string) planner.Predicate {
+		return func(currentState status.Stater) bool {
+			states := currentState.Get(task(id))
+			return len(states) > 0 && states[len(states)-1] == status.Success
+		}
+	}
+
+	It("runs the steps when the predicate is true", func() {
+		plan, err := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			err := plan.When(succeeded("A"), func(plan planner.Planner) error {
+				plan.Task(task("B"))
+				plan.Task(task("C"))
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			plan.Task(task("D"))
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+
+		Expect(plan.Next(state)).To(EqualTasks([]task{"B"}))
+		Expect(plan.State(state)).To(Equal(status.Running))
+
+		Expect(state.Add(task("B"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{"C"}))
+
+		Expect(state.Add(task("C"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{"D"}))
+
+		Expect(state.Add(task("D"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{}))
+		Expect(plan.State(state)).To(Equal(status.Success))
+	})
+
+	It("skips the steps when the predicate is false", func() {
+		plan, err := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			err := plan.When(succeeded("Z"), func(plan planner.Planner) error {
+				plan.Task(task("B"))
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			plan.Task(task("D"))
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+
+		Expect(plan.Next(state)).To(EqualTasks([]task{"D"}))
+
+		Expect(state.Add(task("D"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{}))
+		Expect(plan.State(state)).To(Equal(status.Success))
+
+		conditional := plan.Tree().Children()[1]
+		Expect(conditional.Type()).To(Equal(planner.Conditional))
+		Expect(conditional.State(state)).To(Equal(status.Skipped))
+	})
+
+	It("evaluates the predicate once the step becomes runnable", func() {
+		holds, evaluations := false, 0
+		plan, err := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			err := plan.When(func(status.Stater) bool {
+				evaluations++
+				return holds
+			}, func(plan planner.Planner) error {
+				plan.Task(task("B"))
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			plan.Task(task("C"))
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		state := newStatuses()
+		Expect(plan.Next(state)).To(EqualTasks([]task{"A"}))
+
+		holds = true
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{"B"}))
+
+		evaluated := evaluations
+		holds = false
+		Expect(plan.State(state)).To(Equal(status.Running))
+		Expect(plan.Next(state)).To(EqualTasks([]task{"B"}))
+
+		Expect(state.Add(task("B"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{"C"}))
+		Expect(plan.Tree().Children()[1].State(state)).To(Equal(status.Success))
+		Expect(evaluations).To(Equal(evaluated))
+	})
+
+	It("remembers a skipped step once it was runnable", func() {
+		holds := false
+		plan, err := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			err := plan.When(func(status.Stater) bool {
+				return holds
+			}, func(plan planner.Planner) error {
+				plan.Task(task("B"))
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+			plan.Task(task("C"))
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{"C"}))
+
+		holds = true
+		Expect(plan.Next(state)).To(EqualTasks([]task{"C"}))
+		Expect(plan.Tree().Children()[1].State(state)).To(Equal(status.Skipped))
+
+		state = newStatuses()
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.Next(state)).To(EqualTasks([]task{"B"}))
+	})
+
+	It("treats a skipped step as complete in parallel", func() {
+		plan, err := planner.NewParallel(func(plan planner.Planner) error {
+			plan.Task(task("A"))
+			return plan.When(succeeded("Z"), func(plan planner.Planner) error {
+				plan.Task(task("B"))
+				return nil
+			})
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(plan.Next(newStatuses())).To(EqualTasks([]task{"A"}))
+
+		state := newStatuses()
+		Expect(state.Add(task("A"), status.Failed)).ToNot(HaveOccurred())
+		Expect(plan.State(state)).To(Equal(status.Failed))
+
+		state = newStatuses()
+		Expect(state.Add(task("A"), status.Success)).ToNot(HaveOccurred())
+		Expect(plan.State(state)).To(Equal(status.Success))
+	})
+
+	It("satisfies graph dependencies when skipped", func() {
+		plan, err := planner.NewGraph(func(graph planner.Grapher) error {
+			_, err := graph.Serial("deploy", func(plan planner.Planner) error {
+				return plan.When(succeeded("Z"), func(plan planner.Planner) error {
+					plan.Task(task("A"))
+					return nil
+				})
+			})
+			Expect(err).NotTo(HaveOccurred())
+			graph.Task(task("B")).DependsOn("deploy")
+			return nil
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(plan.Next(newStatuses())).To(EqualTasks([]task{"B"}))
+	})
+})
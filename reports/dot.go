@@ -0,0 +1,58 @@
+package reports
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+type dot struct {
+	plan   planner.Step
+	stater status.Stater
+}
+
+func NewDot(
+	plan planner.Step,
+	stater status.Stater,
+) *dot {
+	return &dot{
+		plan:   plan,
+		stater: stater,
+	}
+}
+
+func (d *dot) Write(w io.Writer) error {
+	g := newGraph(d.plan.Tree(), d.stater)
+	buffer := bufio.NewWriter(w)
+
+	_, _ = fmt.Fprintln(buffer, "digraph plan {")
+	_, _ = fmt.Fprintln(buffer, "  node [shape=box];")
+	for _, node := range g.nodes {
+		switch {
+		case !node.task:
+			_, _ = fmt.Fprintf(buffer, "  %s [label=%s, shape=ellipse];\n", node.id, strconv.Quote(node.label))
+		case node.color != "":
+			_, _ = fmt.Fprintf(buffer, "  %s [label=%s, style=filled, fillcolor=%q];\n", node.id, strconv.Quote(node.label), node.color)
+		default:
+			_, _ = fmt.Fprintf(buffer, "  %s [label=%s];\n", node.id, strconv.Quote(node.label))
+		}
+	}
+	for _, edge := range g.edges {
+		if edge.label != "" {
+			_, _ = fmt.Fprintf(buffer, "  %s -> %s [label=%q];\n", edge.from, edge.to, edge.label)
+		} else {
+			_, _ = fmt.Fprintf(buffer, "  %s -> %s;\n", edge.from, edge.to)
+		}
+	}
+	_, _ = fmt.Fprintln(buffer, "}")
+
+	err := buffer.Flush()
+	if err != nil {
+		return fmt.Errorf("could not write dot graph: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package reports_test
+
+import (
+	"bytes"
+
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/reports"
+	"github.com/jtarchie/dothings/status"
+	"github.com/jtarchie/dothings/tasks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Dot", func() {
+	var plan planner.Step
+
+	BeforeEach(func() {
+		plan, _ = planner.NewSerial(func(plan planner.Planner) error {
+			err := plan.Parallel(func(plan planner.Planner) error {
+				plan.Task(tasks.NewEcho("task 1", status.Success))
+				plan.Task(tasks.NewEcho(`task "2"`, status.Success))
+				return nil
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			return plan.Failure(func(plan planner.Planner) error {
+				plan.Task(tasks.NewEcho("notify", status.Success))
+				return nil
+			})
+		})
+	})
+
+	It("renders the plan as a digraph with labelled hook edges", func() {
+		output := &bytes.Buffer{}
+		err := reports.NewDot(plan, nil).Write(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output.String()).To(Equal(`digraph plan {
+  node [shape=box];
+  n0 [label="serial", shape=ellipse];
+  n1 [label="parallel", shape=ellipse];
+  n2 [label="task 1"];
+  n3 [label="task \"2\""];
+  n4 [label="notify"];
+  n1 -> n2;
+  n1 -> n3;
+  n0 -> n1;
+  n0 -> n4 [label="failure"];
+}
+`))
+	})
+
+	It("colors tasks by their current status", func() {
+		statuses := status.NewStatuses()
+		Expect(statuses.Add(tasks.NewEcho("task 1", status.Success), status.Unstarted)).NotTo(HaveOccurred())
+		Expect(statuses.Add(tasks.NewEcho("task 1", status.Success), status.Running)).NotTo(HaveOccurred())
+
+		output := &bytes.Buffer{}
+		err := reports.NewDot(plan, statuses).Write(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output.String()).To(ContainSubstring(`n2 [label="task 1", style=filled, fillcolor="#0074d9"];`))
+		Expect(output.String()).To(ContainSubstring(`n4 [label="notify", style=filled, fillcolor="#bbbbbb"];`))
+	})
+
+	It("renders graph dependencies as labelled edges", func() {
+		plan, _ := planner.NewGraph(func(graph planner.Grapher) error {
+			graph.Task(tasks.NewEcho("build", status.Success))
+			graph.Task(tasks.NewEcho("test", status.Success)).DependsOn("build")
+			return nil
+		})
+
+		output := &bytes.Buffer{}
+		err := reports.NewDot(plan, nil).Write(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output.String()).To(ContainSubstring(`n0 [label="graph", shape=ellipse];`))
+		Expect(output.String()).To(ContainSubstring(`n2 -> n1 [label="needs"];`))
+	})
+})
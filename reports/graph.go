@@ -0,0 +1,103 @@
+package reports
+
+import (
+	"fmt"
+
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+var statusColors = map[status.Type]string{
+	status.Unstarted: "#bbbbbb",
+	status.Running:   "#0074d9",
+	status.Success:   "#2ecc40",
+	status.Failed:    "#ff4136",
+	status.Errored:   "#f5a623",
+	status.Skipped:   "#dddddd",
+	status.Pending:   "#b10dc9",
+}
+
+type graphNode struct {
+	id    string
+	label string
+	task  bool
+	color string
+}
+
+type graphEdge struct {
+	from  string
+	to    string
+	label string
+}
+
+type graph struct {
+	nodes    []graphNode
+	edges    []graphEdge
+	statuses *taskStatuses
+}
+
+func isHook(node planner.Tree) bool {
+	switch node.Type() {
+	case planner.Success, planner.Failure, planner.Error, planner.Finally:
+		return true
+	}
+	return false
+}
+
+func newGraph(tree planner.Tree, stater status.Stater) *graph {
+	g := &graph{}
+	if stater != nil {
+		g.statuses = newTaskStatuses(tree, stater)
+	}
+	g.add(tree)
+	return g
+}
+
+func (g *graph) add(tree planner.Tree) string {
+	node := graphNode{
+		id:    fmt.Sprintf("n%d", len(g.nodes)),
+		label: tree.Type().String(),
+	}
+	if tree.Type() == planner.Task {
+		node.label = tree.Task().ID()
+		node.task = true
+		if g.statuses != nil {
+			node.color = statusColors[g.statuses.get(tree.Task())]
+		}
+	}
+	g.nodes = append(g.nodes, node)
+
+	ids := map[string]string{}
+	for _, child := range tree.Children() {
+		if isHook(child) {
+			for _, hook := range child.Children() {
+				g.edges = append(g.edges, graphEdge{
+					from:  node.id,
+					to:    g.add(hook),
+					label: child.Type().String(),
+				})
+			}
+			continue
+		}
+
+		ids[child.ID()] = g.add(child)
+		g.edges = append(g.edges, graphEdge{
+			from: node.id,
+			to:   ids[child.ID()],
+		})
+	}
+
+	if tree.Type() == planner.Graph {
+		for _, child := range tree.Children() {
+			for _, id := range child.DependsOn() {
+				g.edges = append(g.edges, graphEdge{
+					from:  ids[child.ID()],
+					to:    ids[id],
+					label: "needs",
+				})
+			}
+		}
+	}
+
+	return node.id
+}
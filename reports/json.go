@@ -0,0 +1,81 @@
+package reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+type jsonTask struct {
+	ID       string `json:"id"`
+	Status   string `json:"status"`
+	Attempts int    `json:"attempts"`
+	Error    string `json:"error,omitempty"`
+	ExitCode *int   `json:"exit_code,omitempty"`
+}
+
+type jsonSummary struct {
+	Status string         `json:"status"`
+	Counts map[string]int `json:"counts"`
+	Tasks  []jsonTask     `json:"tasks"`
+}
+
+type jsonReport struct {
+	plan   planner.Step
+	stater status.Stater
+}
+
+func NewJSON(
+	plan planner.Step,
+	stater status.Stater,
+) *jsonReport {
+	return &jsonReport{
+		plan:   plan,
+		stater: stater,
+	}
+}
+
+func (j *jsonReport) Write(w io.Writer) error {
+	summary := jsonSummary{
+		Status: j.plan.State(j.stater).String(),
+		Counts: map[string]int{},
+		Tasks:  []jsonTask{},
+	}
+
+	tree := j.plan.Tree()
+	j.addTasks(&summary, tree, newTaskStatuses(tree, j.stater))
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(summary)
+	if err != nil {
+		return fmt.Errorf("could not write json report: %s", err)
+	}
+	return nil
+}
+
+func (j *jsonReport) addTasks(summary *jsonSummary, tree planner.Tree, statuses *taskStatuses) {
+	if tree.Type() == planner.Task {
+		currentStatus := statuses.get(tree.Task()).String()
+		summary.Counts[currentStatus]++
+		task := jsonTask{
+			ID:       tree.Task().ID(),
+			Status:   currentStatus,
+			Attempts: len(j.stater.Get(tree.Task())),
+		}
+		if attempts := j.stater.Attempts(tree.Task()); len(attempts) > 0 {
+			latest := attempts[len(attempts)-1]
+			task.Error = latest.Error
+			task.ExitCode = latest.ExitCode
+		}
+		summary.Tasks = append(summary.Tasks, task)
+		return
+	}
+
+	for _, child := range tree.Children() {
+		j.addTasks(summary, child, statuses)
+	}
+}
@@ -0,0 +1,66 @@
+package reports_test
+
+import (
+	"bytes"
+
+	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/executor/writers"
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/reports"
+	"github.com/jtarchie/dothings/status"
+	"github.com/jtarchie/dothings/tasks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JSON", func() {
+	It("writes a flat summary of every task", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(tasks.NewEcho("task 1", status.Success))
+			return plan.Parallel(func(plan planner.Planner) error {
+				plan.Task(tasks.NewEcho("task 2", status.Failed))
+				plan.Task(tasks.NewEcho("task 3", status.Success))
+				return nil
+			})
+		})
+
+		statuses := status.NewStatuses()
+		executor.NewExecutorWithStater(plan, writers.NewInMemory(), statuses).Wait()
+
+		output := &bytes.Buffer{}
+		err := reports.NewJSON(plan, statuses).Write(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output.String()).To(MatchJSON(`{
+			"status": "failed",
+			"counts": {"success": 2, "failed": 1},
+			"tasks": [
+				{"id": "task 1", "status": "success", "attempts": 1},
+				{"id": "task 2", "status": "failed", "attempts": 1},
+				{"id": "task 3", "status": "success", "attempts": 1}
+			]
+		}`))
+	})
+
+	It("includes the error and exit code of failed tasks", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(tasks.NewCommand("bash", "-c", "exit 3"))
+			return nil
+		})
+
+		statuses := status.NewStatuses()
+		executor.NewExecutorWithStater(plan, writers.NewInMemory(), statuses).Wait()
+
+		output := &bytes.Buffer{}
+		err := reports.NewJSON(plan, statuses).Write(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output.String()).To(MatchJSON(`{
+			"status": "failed",
+			"counts": {"failed": 1},
+			"tasks": [
+				{"id": "command: bash -c exit 3", "status": "failed", "attempts": 1, "error": "exit status 3", "exit_code": 3}
+			]
+		}`))
+	})
+})
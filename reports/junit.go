@@ -0,0 +1,170 @@
+package reports
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+
+	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Errors   int              `xml:"errors,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Errors    int             `xml:"errors,attr"`
+	Skipped   int             `xml:"skipped,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitMessage struct {
+	Message string `xml:"message,attr,omitempty"`
+	Body    string `xml:",cdata"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Failure   *junitMessage `xml:"failure,omitempty"`
+	Error     *junitMessage `xml:"error,omitempty"`
+	Skipped   *junitMessage `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+	SystemErr string        `xml:"system-err,omitempty"`
+}
+
+type junit struct {
+	plan     planner.Step
+	writer   executor.Writer
+	stater   status.Stater
+	statuses *taskStatuses
+}
+
+func NewJUnit(
+	plan planner.Step,
+	writer executor.Writer,
+	stater status.Stater,
+) *junit {
+	return &junit{
+		plan:   plan,
+		writer: writer,
+		stater: stater,
+	}
+}
+
+func (j *junit) Write(w io.Writer) error {
+	tree := j.plan.Tree()
+	j.statuses = newTaskStatuses(tree, j.stater)
+	report := junitTestSuites{
+		Name: tree.Type().String(),
+	}
+
+	j.addSuites(&report, tree, tree.Type().String())
+
+	for _, suite := range report.Suites {
+		report.Tests += suite.Tests
+		report.Failures += suite.Failures
+		report.Errors += suite.Errors
+		report.Skipped += suite.Skipped
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return fmt.Errorf("could not write junit header: %s", err)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(report)
+	if err != nil {
+		return fmt.Errorf("could not write junit report: %s", err)
+	}
+
+	_, err = io.WriteString(w, "\n")
+	return err
+}
+
+func (j *junit) addSuites(report *junitTestSuites, tree planner.Tree, name string) {
+	suite := junitTestSuite{
+		Name: name,
+	}
+
+	for index, child := range tree.Children() {
+		if child.Type() != planner.Task {
+			j.addSuites(report, child, fmt.Sprintf("%s/%s-%d", name, child.Type(), index))
+			continue
+		}
+
+		testCase := j.testCase(child.Task(), name)
+		suite.Tests++
+		switch {
+		case testCase.Failure != nil:
+			suite.Failures++
+		case testCase.Error != nil:
+			suite.Errors++
+		case testCase.Skipped != nil:
+			suite.Skipped++
+		}
+		suite.TestCases = append(suite.TestCases, testCase)
+	}
+
+	if suite.Tests > 0 {
+		report.Suites = append(report.Suites, suite)
+	}
+}
+
+func (j *junit) testCase(task planner.Tasker, className string) junitTestCase {
+	stdout, stderr := j.writer.GetString(task)
+	testCase := junitTestCase{
+		Name:      task.ID(),
+		ClassName: className,
+		SystemOut: stdout,
+	}
+	if stderr != stdout {
+		testCase.SystemErr = stderr
+	}
+
+	switch currentStatus := j.statuses.get(task); currentStatus {
+	case status.Success:
+	case status.Failed:
+		testCase.Failure = &junitMessage{
+			Message: j.message(task, currentStatus),
+			Body:    stdout,
+		}
+	case status.Errored:
+		testCase.Error = &junitMessage{
+			Message: j.message(task, currentStatus),
+			Body:    stdout,
+		}
+	default:
+		testCase.Skipped = &junitMessage{
+			Message: fmt.Sprintf("task %s", currentStatus),
+		}
+	}
+
+	return testCase
+}
+
+func (j *junit) message(task planner.Tasker, currentStatus status.Type) string {
+	attempts := j.stater.Attempts(task)
+	if len(attempts) == 0 || attempts[len(attempts)-1].Error == "" {
+		return fmt.Sprintf("task %s", currentStatus)
+	}
+
+	latest := attempts[len(attempts)-1]
+	if latest.ExitCode != nil {
+		return fmt.Sprintf("task %s: %s (exit code %d)", currentStatus, latest.Error, *latest.ExitCode)
+	}
+	return fmt.Sprintf("task %s: %s", currentStatus, latest.Error)
+}
@@ -0,0 +1,103 @@
+package reports_test
+
+import (
+	"bytes"
+	"encoding/xml"
+
+	"github.com/jtarchie/dothings/executor"
+	"github.com/jtarchie/dothings/executor/writers"
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/reports"
+	"github.com/jtarchie/dothings/status"
+	"github.com/jtarchie/dothings/tasks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("JUnit", func() {
+	It("writes groups as testsuites and tasks as testcases", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(tasks.NewEcho("task 1", status.Success))
+			return plan.Parallel(func(plan planner.Planner) error {
+				plan.Task(tasks.NewEcho("task 2", status.Failed))
+				plan.Task(tasks.NewEcho("task 3", status.Errored))
+				return nil
+			})
+		})
+
+		inMemory := writers.NewInMemory()
+		statuses := status.NewStatuses()
+		executor.NewExecutorWithStater(plan, inMemory, statuses).Wait()
+
+		output := &bytes.Buffer{}
+		err := reports.NewJUnit(plan, inMemory, statuses).Write(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output.String()).To(HavePrefix(xml.Header))
+		Expect(output.String()).To(ContainSubstring(`<testsuites name="serial" tests="3" failures="1" errors="1" skipped="0">`))
+		Expect(output.String()).To(ContainSubstring(`<testsuite name="serial" tests="1" failures="0" errors="0" skipped="0">`))
+		Expect(output.String()).To(ContainSubstring(`<testcase name="task 1" classname="serial">`))
+		Expect(output.String()).To(ContainSubstring(`<testsuite name="serial/parallel-1" tests="2" failures="1" errors="1" skipped="0">`))
+		Expect(output.String()).To(ContainSubstring(`<failure message="task failed"><![CDATA[out: executing task 2`))
+		Expect(output.String()).To(ContainSubstring(`<error message="task errored"><![CDATA[out: executing task 3`))
+	})
+
+	It("marks tasks that never ran as skipped", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(tasks.NewEcho("task 1", status.Failed))
+			plan.Task(tasks.NewEcho("task 2", status.Success))
+			return nil
+		})
+
+		inMemory := writers.NewInMemory()
+		statuses := status.NewStatuses()
+		executor.NewExecutorWithStater(plan, inMemory, statuses).Wait()
+
+		output := &bytes.Buffer{}
+		err := reports.NewJUnit(plan, inMemory, statuses).Write(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output.String()).To(ContainSubstring(`<testsuites name="serial" tests="2" failures="1" errors="0" skipped="1">`))
+		Expect(output.String()).To(ContainSubstring(`<skipped message="task unstarted">`))
+	})
+
+	It("marks tasks in a conditional step that did not run as skipped", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(tasks.NewEcho("task 1", status.Success))
+			return plan.When(func(status.Stater) bool {
+				return false
+			}, func(plan planner.Planner) error {
+				plan.Task(tasks.NewEcho("task 2", status.Success))
+				return nil
+			})
+		})
+
+		inMemory := writers.NewInMemory()
+		statuses := status.NewStatuses()
+		Expect(executor.NewExecutorWithStater(plan, inMemory, statuses).Wait()).To(Equal(status.Success))
+
+		output := &bytes.Buffer{}
+		err := reports.NewJUnit(plan, inMemory, statuses).Write(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output.String()).To(ContainSubstring(`<testsuite name="serial/conditional-1" tests="1" failures="0" errors="0" skipped="1">`))
+		Expect(output.String()).To(ContainSubstring(`<skipped message="task skipped">`))
+	})
+
+	It("includes the error and exit code in the failure message", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(tasks.NewCommand("bash", "-c", "exit 3"))
+			return nil
+		})
+
+		inMemory := writers.NewInMemory()
+		statuses := status.NewStatuses()
+		executor.NewExecutorWithStater(plan, inMemory, statuses).Wait()
+
+		output := &bytes.Buffer{}
+		err := reports.NewJUnit(plan, inMemory, statuses).Write(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output.String()).To(ContainSubstring(`<failure message="task failed: exit status 3 (exit code 3)">`))
+	})
+})
@@ -0,0 +1,64 @@
+package reports
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+var mermaidEscaper = strings.NewReplacer(
+	`"`, "#quot;",
+	"\n", " ",
+)
+
+type mermaid struct {
+	plan   planner.Step
+	stater status.Stater
+}
+
+func NewMermaid(
+	plan planner.Step,
+	stater status.Stater,
+) *mermaid {
+	return &mermaid{
+		plan:   plan,
+		stater: stater,
+	}
+}
+
+func (m *mermaid) Write(w io.Writer) error {
+	g := newGraph(m.plan.Tree(), m.stater)
+	buffer := bufio.NewWriter(w)
+
+	_, _ = fmt.Fprintln(buffer, "flowchart TD")
+	for _, node := range g.nodes {
+		label := mermaidEscaper.Replace(node.label)
+		if node.task {
+			_, _ = fmt.Fprintf(buffer, "  %s[\"%s\"]\n", node.id, label)
+		} else {
+			_, _ = fmt.Fprintf(buffer, "  %s([\"%s\"])\n", node.id, label)
+		}
+	}
+	for _, edge := range g.edges {
+		if edge.label != "" {
+			_, _ = fmt.Fprintf(buffer, "  %s -->|%s| %s\n", edge.from, edge.label, edge.to)
+		} else {
+			_, _ = fmt.Fprintf(buffer, "  %s --> %s\n", edge.from, edge.to)
+		}
+	}
+	for _, node := range g.nodes {
+		if node.color != "" {
+			_, _ = fmt.Fprintf(buffer, "  style %s fill:%s\n", node.id, node.color)
+		}
+	}
+
+	err := buffer.Flush()
+	if err != nil {
+		return fmt.Errorf("could not write mermaid graph: %s", err)
+	}
+	return nil
+}
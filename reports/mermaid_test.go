@@ -0,0 +1,41 @@
+package reports_test
+
+import (
+	"bytes"
+
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/reports"
+	"github.com/jtarchie/dothings/status"
+	"github.com/jtarchie/dothings/tasks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Mermaid", func() {
+	It("renders the plan as a flowchart colored by status", func() {
+		plan, _ := planner.NewSerial(func(plan planner.Planner) error {
+			plan.Task(tasks.NewEcho(`task "1"`, status.Success))
+			return plan.Finally(func(plan planner.Planner) error {
+				plan.Task(tasks.NewEcho("cleanup", status.Success))
+				return nil
+			})
+		})
+
+		statuses := status.NewStatuses()
+		Expect(statuses.Add(tasks.NewEcho(`task "1"`, status.Success), status.Unstarted)).NotTo(HaveOccurred())
+
+		output := &bytes.Buffer{}
+		err := reports.NewMermaid(plan, statuses).Write(output)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(output.String()).To(Equal(`flowchart TD
+  n0(["serial"])
+  n1["task #quot;1#quot;"]
+  n2["cleanup"]
+  n0 --> n1
+  n0 -->|finally| n2
+  style n1 fill:#bbbbbb
+  style n2 fill:#bbbbbb
+`))
+	})
+})
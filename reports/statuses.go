@@ -0,0 +1,45 @@
+package reports
+
+import (
+	"github.com/jtarchie/dothings/planner"
+	"github.com/jtarchie/dothings/status"
+)
+
+type taskStatuses struct {
+	stater  status.Stater
+	skipped map[string]bool
+}
+
+func newTaskStatuses(tree planner.Tree, stater status.Stater) *taskStatuses {
+	skipped := map[string]bool{}
+
+	_ = tree.Walk(func(node planner.Tree, _ []planner.Tree) error {
+		if node.Type() != planner.Conditional || node.State(stater) != status.Skipped {
+			return nil
+		}
+
+		_ = node.Walk(func(node planner.Tree, _ []planner.Tree) error {
+			if node.Type() == planner.Task {
+				skipped[node.Task().ID()] = true
+			}
+			return nil
+		})
+		return planner.SkipChildren
+	})
+
+	return &taskStatuses{
+		stater:  stater,
+		skipped: skipped,
+	}
+}
+
+func (t *taskStatuses) get(task status.Identifier) status.Type {
+	states := t.stater.Get(task)
+	if len(states) > 0 {
+		return states[len(states)-1]
+	}
+	if t.skipped[task.ID()] {
+		return status.Skipped
+	}
+	return status.Unstarted
+}
@@ -0,0 +1,116 @@
+package status
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+type Transition struct {
+	Status Type      `json:"status"`
+	At     time.Time `json:"at"`
+}
+
+type Attempt struct {
+	Transitions []Transition `json:"transitions"`
+	Error       string       `json:"error,omitempty"`
+	ExitCode    *int         `json:"exit_code,omitempty"`
+}
+
+func ExitCode(err error) (int, bool) {
+	var coder interface{ ExitCode() int }
+	if errors.As(err, &coder) {
+		return coder.ExitCode(), true
+	}
+	return 0, false
+}
+
+type recordedError struct {
+	message  string
+	exitCode *int
+}
+
+func NewError(message string, exitCode *int) error {
+	return recordedError{message: message, exitCode: exitCode}
+}
+
+func (r recordedError) Error() string {
+	return r.message
+}
+
+func (r recordedError) Unwrap() error {
+	if r.exitCode == nil {
+		return nil
+	}
+	return exitError{code: *r.exitCode}
+}
+
+type exitError struct {
+	code int
+}
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+func (e exitError) ExitCode() int {
+	return e.code
+}
+
+func (a Attempt) Status() Type {
+	if len(a.Transitions) == 0 {
+		return Unstarted
+	}
+	return a.Transitions[len(a.Transitions)-1].Status
+}
+
+func (a Attempt) QueuedAt() time.Time {
+	if len(a.Transitions) == 0 {
+		return time.Time{}
+	}
+	return a.Transitions[0].At
+}
+
+func (a Attempt) StartedAt() (time.Time, bool) {
+	for _, transition := range a.Transitions {
+		if transition.Status == Running {
+			return transition.At, true
+		}
+	}
+	return time.Time{}, false
+}
+
+func (a Attempt) FinishedAt() (time.Time, bool) {
+	if len(a.Transitions) > 0 && finalState(a.Status()) {
+		return a.Transitions[len(a.Transitions)-1].At, true
+	}
+	return time.Time{}, false
+}
+
+func (a Attempt) Queued(now time.Time) time.Duration {
+	if len(a.Transitions) == 0 {
+		return 0
+	}
+	if started, ok := a.StartedAt(); ok {
+		return started.Sub(a.QueuedAt())
+	}
+	if finished, ok := a.FinishedAt(); ok {
+		return finished.Sub(a.QueuedAt())
+	}
+	return now.Sub(a.QueuedAt())
+}
+
+func (a Attempt) Ran(now time.Time) time.Duration {
+	started, ok := a.StartedAt()
+	if !ok {
+		return 0
+	}
+	if finished, ok := a.FinishedAt(); ok {
+		return finished.Sub(started)
+	}
+	return now.Sub(started)
+}
+
+func (a Attempt) Duration(now time.Time) time.Duration {
+	return a.Queued(now) + a.Ran(now)
+}
@@ -0,0 +1,142 @@
+package status_test
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	. "github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Attempts", func() {
+	var start time.Time
+
+	BeforeEach(func() {
+		start = time.Date(2019, 8, 1, 12, 0, 0, 0, time.UTC)
+	})
+
+	at := func(offset time.Duration, s Type) Transition {
+		return Transition{Status: s, At: start.Add(offset)}
+	}
+
+	It("reports the time queued and running for a finished attempt", func() {
+		attempt := Attempt{Transitions: []Transition{
+			at(0, Unstarted),
+			at(2*time.Second, Running),
+			at(7*time.Second, Success),
+		}}
+		now := start.Add(time.Hour)
+
+		Expect(attempt.Status()).To(Equal(Success))
+		Expect(attempt.QueuedAt()).To(Equal(start))
+		Expect(attempt.Queued(now)).To(Equal(2 * time.Second))
+		Expect(attempt.Ran(now)).To(Equal(5 * time.Second))
+		Expect(attempt.Duration(now)).To(Equal(7 * time.Second))
+
+		finished, ok := attempt.FinishedAt()
+		Expect(ok).To(BeTrue())
+		Expect(finished).To(Equal(start.Add(7 * time.Second)))
+	})
+
+	It("reports elapsed time for an attempt still running", func() {
+		attempt := Attempt{Transitions: []Transition{
+			at(0, Unstarted),
+			at(time.Second, Running),
+			at(3*time.Second, Pending),
+		}}
+		now := start.Add(10 * time.Second)
+
+		Expect(attempt.Status()).To(Equal(Pending))
+		Expect(attempt.Queued(now)).To(Equal(time.Second))
+		Expect(attempt.Ran(now)).To(Equal(9 * time.Second))
+
+		_, ok := attempt.FinishedAt()
+		Expect(ok).To(BeFalse())
+	})
+
+	It("reports only queued time for an attempt not yet started", func() {
+		attempt := Attempt{Transitions: []Transition{at(0, Unstarted)}}
+		now := start.Add(4 * time.Second)
+
+		_, ok := attempt.StartedAt()
+		Expect(ok).To(BeFalse())
+		Expect(attempt.Queued(now)).To(Equal(4 * time.Second))
+		Expect(attempt.Ran(now)).To(BeZero())
+	})
+
+	It("is unstarted without any transitions", func() {
+		attempt := Attempt{}
+		Expect(attempt.Status()).To(Equal(Unstarted))
+		Expect(attempt.Duration(start)).To(BeZero())
+	})
+
+	It("is recorded for every attempt in the statuses", func() {
+		statuses := NewStatuses()
+		Expect(statuses.Attempts(task("A"))).To(BeEmpty())
+
+		before := time.Now()
+		for _, s := range []Type{Unstarted, Running, Errored, Unstarted, Running, Success} {
+			Expect(statuses.Add(task("A"), s)).To(Succeed())
+		}
+		Expect(statuses.Add(task("A"), Running)).NotTo(Succeed())
+
+		attempts := statuses.Attempts(task("A"))
+		Expect(attempts).To(HaveLen(2))
+
+		statusesOf := func(attempt Attempt) []Type {
+			types := []Type{}
+			for _, transition := range attempt.Transitions {
+				Expect(transition.At).To(BeTemporally(">=", before))
+				types = append(types, transition.Status)
+			}
+			return types
+		}
+		Expect(statusesOf(attempts[0])).To(Equal([]Type{Unstarted, Running, Errored}))
+		Expect(statusesOf(attempts[1])).To(Equal([]Type{Unstarted, Running, Success}))
+	})
+
+	It("records transitions with the given clock", func() {
+		now := start
+		statuses := NewStatusesWithClock(func() time.Time { return now })
+
+		Expect(statuses.Add(task("A"), Unstarted)).To(Succeed())
+		now = now.Add(time.Minute)
+		Expect(statuses.Add(task("A"), Running)).To(Succeed())
+
+		Expect(statuses.Attempts(task("A"))[0].Transitions).To(Equal([]Transition{
+			at(0, Unstarted),
+			at(time.Minute, Running),
+		}))
+	})
+
+	It("records an error on the latest attempt", func() {
+		statuses := NewStatuses()
+		Expect(statuses.AddError(task("A"), errors.New("boom"))).NotTo(Succeed())
+
+		for _, s := range []Type{Unstarted, Running, Errored, Unstarted, Running, Failed} {
+			Expect(statuses.Add(task("A"), s)).To(Succeed())
+		}
+		Expect(statuses.AddError(task("A"), exitError(2))).To(Succeed())
+
+		attempts := statuses.Attempts(task("A"))
+		Expect(attempts[0].Error).To(BeEmpty())
+		Expect(attempts[0].ExitCode).To(BeNil())
+		Expect(attempts[1].Error).To(Equal("exit status 2"))
+		Expect(*attempts[1].ExitCode).To(Equal(2))
+	})
+
+	It("finds exit codes in wrapped errors", func() {
+		code, ok := ExitCode(fmt.Errorf("could not run: %w", exitError(7)))
+		Expect(ok).To(BeTrue())
+		Expect(code).To(Equal(7))
+
+		_, ok = ExitCode(errors.New("boom"))
+		Expect(ok).To(BeFalse())
+	})
+})
+
+func exitError(code int) error {
+	return NewError(fmt.Sprintf("exit status %d", code), &code)
+}
@@ -0,0 +1,180 @@
+package status
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+type client struct {
+	url  string
+	http *http.Client
+
+	lock     sync.Mutex
+	statuses map[string][]Type
+	attempts map[string][]Attempt
+}
+
+var _ Stater = &client{}
+
+func NewClient(url string) Stater {
+	return NewClientWithHTTP(url, &http.Client{})
+}
+
+func NewUnixClient(socket string) Stater {
+	return NewClientWithHTTP("http://unix", &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", socket)
+			},
+		},
+	})
+}
+
+func NewClientWithHTTP(url string, httpClient *http.Client) Stater {
+	return &client{
+		url:      strings.TrimSuffix(url, "/"),
+		http:     httpClient,
+		statuses: map[string][]Type{},
+		attempts: map[string][]Attempt{},
+	}
+}
+
+func (c *client) Get(task Identifier) []Type {
+	statuses := []Type{}
+	err := c.get("/statuses", task, &statuses)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err != nil {
+		log.Printf("could not get statuses of %s, using the last known: %s", task.ID(), err)
+		return append([]Type{}, c.statuses[task.ID()]...)
+	}
+	c.statuses[task.ID()] = statuses
+	return statuses
+}
+
+func (c *client) Attempts(task Identifier) []Attempt {
+	attempts := []Attempt{}
+	err := c.get("/attempts", task, &attempts)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if err != nil {
+		log.Printf("could not get attempts of %s, using the last known: %s", task.ID(), err)
+		return append([]Attempt{}, c.attempts[task.ID()]...)
+	}
+	c.attempts[task.ID()] = attempts
+	return attempts
+}
+
+func (c *client) Add(task Identifier, s Type) error {
+	err := c.post("/statuses", transitionRequest{ID: task.ID(), Status: s})
+	if err != nil {
+		return err
+	}
+
+	c.Get(task)
+	return nil
+}
+
+func (c *client) AddError(task Identifier, err error) error {
+	request := errorRequest{ID: task.ID(), Error: err.Error()}
+	if code, ok := ExitCode(err); ok {
+		request.ExitCode = &code
+	}
+	return c.post("/errors", request)
+}
+
+func (c *client) Watch() (<-chan Event, func()) {
+	events := make(chan Event, watchBuffer)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, c.url+"/watch", nil)
+	if err != nil {
+		log.Printf("could not watch statuses: %s", err)
+		close(events)
+		return events, cancel
+	}
+	response, err := c.http.Do(request)
+	if err == nil && response.StatusCode != http.StatusOK {
+		err = responseError(response)
+		response.Body.Close()
+	}
+	if err != nil {
+		log.Printf("could not watch statuses: %s", err)
+		close(events)
+		return events, cancel
+	}
+
+	go func() {
+		defer close(events)
+		defer response.Body.Close()
+
+		scanner := bufio.NewScanner(response.Body)
+		for scanner.Scan() {
+			var event Event
+			err := json.Unmarshal(scanner.Bytes(), &event)
+			if err != nil {
+				log.Printf("could not decode status event: %s", err)
+				return
+			}
+
+			select {
+			case events <- event:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, cancel
+}
+
+func (c *client) get(path string, task Identifier, value interface{}) error {
+	response, err := c.http.Get(fmt.Sprintf("%s%s?id=%s", c.url, path, url.QueryEscape(task.ID())))
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusOK {
+		return responseError(response)
+	}
+	return json.NewDecoder(response.Body).Decode(value)
+}
+
+func (c *client) post(path string, value interface{}) error {
+	body, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("could not encode request: %s", err)
+	}
+
+	response, err := c.http.Post(c.url+path, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("could not reach status server: %s", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode != http.StatusNoContent {
+		return responseError(response)
+	}
+	return nil
+}
+
+func responseError(response *http.Response) error {
+	message, _ := ioutil.ReadAll(response.Body)
+	return fmt.Errorf("%s", strings.TrimSpace(string(message)))
+}
@@ -0,0 +1,40 @@
+package status
+
+import "sync"
+
+type Memo interface {
+	Recall(key string) (bool, bool)
+	Remember(key string, value bool) bool
+}
+
+type memo struct {
+	lock   sync.Mutex
+	values map[string]bool
+}
+
+var _ Memo = &memo{}
+
+func newMemo() *memo {
+	return &memo{
+		values: map[string]bool{},
+	}
+}
+
+func (m *memo) Recall(key string) (bool, bool) {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	value, ok := m.values[key]
+	return value, ok
+}
+
+func (m *memo) Remember(key string, value bool) bool {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	if remembered, ok := m.values[key]; ok {
+		return remembered
+	}
+	m.values[key] = value
+	return value
+}
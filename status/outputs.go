@@ -0,0 +1,65 @@
+package status
+
+import (
+	"context"
+	"sync"
+)
+
+type Outputs interface {
+	Set(task Identifier, key string, value string)
+	Get(id string, key string) (string, bool)
+	Values(id string) map[string]string
+}
+
+type outputs struct {
+	sync.Mutex
+	values map[string]map[string]string
+}
+
+func NewOutputs() Outputs {
+	return &outputs{
+		values: map[string]map[string]string{},
+	}
+}
+
+func (o *outputs) Set(task Identifier, key string, value string) {
+	o.Lock()
+	defer o.Unlock()
+
+	values, ok := o.values[task.ID()]
+	if !ok {
+		values = map[string]string{}
+		o.values[task.ID()] = values
+	}
+	values[key] = value
+}
+
+func (o *outputs) Get(id string, key string) (string, bool) {
+	o.Lock()
+	defer o.Unlock()
+
+	value, ok := o.values[id][key]
+	return value, ok
+}
+
+func (o *outputs) Values(id string) map[string]string {
+	o.Lock()
+	defer o.Unlock()
+
+	tmp := map[string]string{}
+	for key, value := range o.values[id] {
+		tmp[key] = value
+	}
+	return tmp
+}
+
+type outputsKey struct{}
+
+func WithOutputs(ctx context.Context, outputs Outputs) context.Context {
+	return context.WithValue(ctx, outputsKey{}, outputs)
+}
+
+func OutputsFromContext(ctx context.Context) (Outputs, bool) {
+	outputs, ok := ctx.Value(outputsKey{}).(Outputs)
+	return outputs, ok
+}
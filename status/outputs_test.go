@@ -0,0 +1,58 @@
+package status_test
+
+import (
+	"context"
+
+	. "github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Outputs", func() {
+	It("stores values by task ID", func() {
+		outputs := NewOutputs()
+		_, ok := outputs.Get("A", "version")
+		Expect(ok).To(BeFalse())
+
+		outputs.Set(task("A"), "version", "1.2.3")
+		outputs.Set(task("A"), "sha", "abcdef")
+		outputs.Set(task("B"), "version", "4.5.6")
+
+		value, ok := outputs.Get("A", "version")
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal("1.2.3"))
+		Expect(outputs.Values("A")).To(Equal(map[string]string{
+			"version": "1.2.3",
+			"sha":     "abcdef",
+		}))
+		Expect(outputs.Values("C")).To(BeEmpty())
+	})
+
+	It("overwrites values when a task sets them again", func() {
+		outputs := NewOutputs()
+		outputs.Set(task("A"), "version", "1.2.3")
+		outputs.Set(task("A"), "version", "1.2.4")
+
+		value, _ := outputs.Get("A", "version")
+		Expect(value).To(Equal("1.2.4"))
+	})
+
+	It("does not share the returned values", func() {
+		outputs := NewOutputs()
+		outputs.Set(task("A"), "version", "1.2.3")
+		outputs.Values("A")["version"] = "changed"
+
+		value, _ := outputs.Get("A", "version")
+		Expect(value).To(Equal("1.2.3"))
+	})
+
+	It("can be carried by a context", func() {
+		_, ok := OutputsFromContext(context.Background())
+		Expect(ok).To(BeFalse())
+
+		outputs := NewOutputs()
+		found, ok := OutputsFromContext(WithOutputs(context.Background(), outputs))
+		Expect(ok).To(BeTrue())
+		Expect(found).To(BeIdenticalTo(outputs))
+	})
+})
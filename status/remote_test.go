@@ -0,0 +1,130 @@
+package status_test
+
+import (
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Remote statuses", func() {
+	var (
+		local  Stater
+		server *httptest.Server
+		remote Stater
+	)
+
+	BeforeEach(func() {
+		local = NewStatuses()
+		server = httptest.NewServer(NewServer(local))
+		remote = NewClient(server.URL)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("shares statuses with the server", func() {
+		Expect(remote.Get(task("A"))).To(BeEmpty())
+
+		Expect(remote.Add(task("A"), Unstarted)).To(Succeed())
+		Expect(local.Add(task("A"), Running)).To(Succeed())
+		Expect(remote.Add(task("A"), Success)).To(Succeed())
+
+		Expect(remote.Get(task("A"))).To(Equal([]Type{Success}))
+		Expect(local.Get(task("A"))).To(Equal([]Type{Success}))
+		Expect(NewClient(server.URL).Get(task("A"))).To(Equal([]Type{Success}))
+	})
+
+	It("enforces the same transitions as the server", func() {
+		Expect(remote.Add(task("A"), Running)).To(MatchError("the set status running cannot be an initial Stater"))
+		Expect(remote.Add(task("A"), Unstarted)).To(Succeed())
+		Expect(remote.Add(task("A"), Success)).To(MatchError("cannot transition from unstarted to success"))
+	})
+
+	It("shares attempts and errors", func() {
+		Expect(remote.AddError(task("A"), exitError(2))).To(MatchError("cannot add an error to A before it has been queued"))
+
+		for _, s := range []Type{Unstarted, Running, Failed} {
+			Expect(remote.Add(task("A"), s)).To(Succeed())
+		}
+		Expect(remote.AddError(task("A"), exitError(2))).To(Succeed())
+
+		attempts := remote.Attempts(task("A"))
+		Expect(attempts).To(HaveLen(1))
+		Expect(attempts[0].Status()).To(Equal(Failed))
+		Expect(attempts[0].Error).To(Equal("exit status 2"))
+		Expect(*attempts[0].ExitCode).To(Equal(2))
+		Expect(attempts[0].Transitions[0].At).To(BeTemporally("~", local.Attempts(task("A"))[0].Transitions[0].At))
+
+		code, ok := ExitCode(NewError(attempts[0].Error, attempts[0].ExitCode))
+		Expect(ok).To(BeTrue())
+		Expect(code).To(Equal(2))
+	})
+
+	It("keeps the last known statuses when the server fails", func() {
+		broken := false
+		handler := NewServer(local)
+		flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if broken {
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+				return
+			}
+			handler.ServeHTTP(w, r)
+		}))
+		defer flaky.Close()
+		remote := NewClient(flaky.URL)
+
+		for _, s := range []Type{Unstarted, Running, Success} {
+			Expect(remote.Add(task("A"), s)).To(Succeed())
+		}
+		Expect(remote.Attempts(task("A"))).To(HaveLen(1))
+
+		broken = true
+		Expect(remote.Get(task("A"))).To(Equal([]Type{Success}))
+		Expect(remote.Attempts(task("A"))).To(HaveLen(1))
+		Expect(remote.Add(task("A"), Unstarted)).To(MatchError("unavailable"))
+		Expect(remote.Get(task("A"))).To(Equal([]Type{Success}))
+	})
+
+	It("streams transitions to watchers", func() {
+		events, stop := remote.Watch()
+
+		Expect(local.Add(task("A"), Unstarted)).To(Succeed())
+		Expect(remote.Add(task("A"), Running)).To(Succeed())
+
+		var event Event
+		Eventually(events).Should(Receive(&event))
+		Expect([]interface{}{event.TaskID, event.Attempt, event.From, event.To}).To(Equal([]interface{}{"A", 1, Unstarted, Unstarted}))
+		Eventually(events).Should(Receive(&event))
+		Expect([]interface{}{event.TaskID, event.Attempt, event.From, event.To}).To(Equal([]interface{}{"A", 1, Unstarted, Running}))
+
+		stop()
+		Eventually(events, time.Second).Should(BeClosed())
+	})
+
+	It("works over a unix socket", func() {
+		dir, err := ioutil.TempDir("", "statuses")
+		Expect(err).NotTo(HaveOccurred())
+		defer os.RemoveAll(dir)
+
+		socket := filepath.Join(dir, "statuses.sock")
+		listener, err := net.Listen("unix", socket)
+		Expect(err).NotTo(HaveOccurred())
+		go func() {
+			_ = http.Serve(listener, NewServer(local))
+		}()
+		defer listener.Close()
+
+		remote := NewUnixClient(socket)
+		Expect(remote.Add(task("A"), Unstarted)).To(Succeed())
+		Expect(local.Get(task("A"))).To(Equal([]Type{Unstarted}))
+	})
+})
@@ -0,0 +1,83 @@
+package status
+
+import (
+	"fmt"
+	"sync"
+)
+
+type rerun struct {
+	Stater
+	*memo
+	offsets map[string]int
+}
+
+func NewRerun(stater Stater, ids []string) Stater {
+	offsets := map[string]int{}
+	for _, id := range ids {
+		if attempts := len(stater.Get(identifier(id))); attempts > 0 {
+			offsets[id] = attempts
+		}
+	}
+
+	return &rerun{
+		Stater:  stater,
+		memo:    newMemo(),
+		offsets: offsets,
+	}
+}
+
+func (r *rerun) Get(task Identifier) []Type {
+	statuses := r.Stater.Get(task)
+	offset := r.offsets[task.ID()]
+	if offset > len(statuses) {
+		return []Type{}
+	}
+	return statuses[offset:]
+}
+
+func (r *rerun) Attempts(task Identifier) []Attempt {
+	attempts := r.Stater.Attempts(task)
+	offset := r.offsets[task.ID()]
+	if offset > len(attempts) {
+		return []Attempt{}
+	}
+	return attempts[offset:]
+}
+
+func (r *rerun) AddError(task Identifier, err error) error {
+	if len(r.Get(task)) == 0 {
+		return fmt.Errorf("cannot add an error to %s before it has been queued", task.ID())
+	}
+	return r.Stater.AddError(task, err)
+}
+
+func (r *rerun) Watch() (<-chan Event, func()) {
+	events, stop := r.Stater.Watch()
+	done := make(chan struct{})
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			close(done)
+			stop()
+		})
+	}
+
+	rerunEvents := make(chan Event, watchBuffer)
+	go func() {
+		defer close(rerunEvents)
+
+		for event := range events {
+			event.Attempt -= r.offsets[event.TaskID]
+			if event.Attempt < 1 {
+				continue
+			}
+
+			select {
+			case rerunEvents <- event:
+			case <-done:
+			}
+		}
+	}()
+
+	return rerunEvents, unsubscribe
+}
@@ -0,0 +1,58 @@
+package status_test
+
+import (
+	"fmt"
+
+	. "github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Rerun", func() {
+	var previous Stater
+
+	BeforeEach(func() {
+		previous = NewStatuses()
+		for _, transition := range []struct {
+			task   task
+			status Type
+		}{
+			{"A", Unstarted}, {"A", Running}, {"A", Success},
+			{"B", Unstarted}, {"B", Running}, {"B", Failed},
+		} {
+			Expect(previous.Add(transition.task, transition.status)).To(Succeed())
+		}
+		Expect(previous.AddError(task("B"), fmt.Errorf("exit status 1"))).To(Succeed())
+	})
+
+	It("hides the previous attempts of reset tasks", func() {
+		rerun := NewRerun(previous, []string{"B", "C"})
+
+		Expect(rerun.Get(task("A"))).To(Equal([]Type{Success}))
+		Expect(rerun.Get(task("B"))).To(Equal([]Type{}))
+		Expect(rerun.Get(task("C"))).To(Equal([]Type{}))
+		Expect(rerun.Attempts(task("B"))).To(BeEmpty())
+		Expect(rerun.AddError(task("B"), fmt.Errorf("too early"))).To(MatchError(ContainSubstring("before it has been queued")))
+	})
+
+	It("appends new attempts to the previous history", func() {
+		rerun := NewRerun(previous, []string{"B"})
+		events, stop := rerun.Watch()
+		defer stop()
+
+		Expect(rerun.Add(task("B"), Unstarted)).To(Succeed())
+		Expect(rerun.Add(task("B"), Running)).To(Succeed())
+		Expect(rerun.Add(task("B"), Success)).To(Succeed())
+
+		Expect(rerun.Get(task("B"))).To(Equal([]Type{Success}))
+		Expect(rerun.Attempts(task("B"))).To(HaveLen(1))
+		Expect(previous.Get(task("B"))).To(Equal([]Type{Failed, Success}))
+		Expect(previous.Attempts(task("B"))[0].Error).To(Equal("exit status 1"))
+
+		event := <-events
+		Expect(event.TaskID).To(Equal("B"))
+		Expect(event.Attempt).To(Equal(1))
+		Expect(event.From).To(Equal(Failed))
+		Expect(event.To).To(Equal(Unstarted))
+	})
+})
@@ -0,0 +1,134 @@
+package status
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+type identifier string
+
+func (i identifier) ID() string {
+	return string(i)
+}
+
+type transitionRequest struct {
+	ID     string `json:"id"`
+	Status Type   `json:"status"`
+}
+
+type errorRequest struct {
+	ID       string `json:"id"`
+	Error    string `json:"error"`
+	ExitCode *int   `json:"exit_code,omitempty"`
+}
+
+type server struct {
+	stater Stater
+	mux    *http.ServeMux
+}
+
+func NewServer(stater Stater) *server {
+	s := &server{
+		stater: stater,
+		mux:    http.NewServeMux(),
+	}
+	s.mux.HandleFunc("/statuses", s.statuses)
+	s.mux.HandleFunc("/attempts", s.attempts)
+	s.mux.HandleFunc("/errors", s.errors)
+	s.mux.HandleFunc("/watch", s.watch)
+	return s
+}
+
+func (s *server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *server) statuses(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		respond(w, s.stater.Get(identifier(r.FormValue("id"))))
+	case http.MethodPost:
+		var request transitionRequest
+		err := json.NewDecoder(r.Body).Decode(&request)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("could not decode transition: %s", err), http.StatusBadRequest)
+			return
+		}
+
+		err = s.stater.Add(identifier(request.ID), request.Status)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *server) attempts(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	respond(w, s.stater.Attempts(identifier(r.FormValue("id"))))
+}
+
+func (s *server) errors(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var request errorRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("could not decode error: %s", err), http.StatusBadRequest)
+		return
+	}
+
+	err = s.stater.AddError(identifier(request.ID), NewError(request.Error, request.ExitCode))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *server) watch(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	events, stop := s.stater.Watch()
+	defer stop()
+
+	w.Header().Set("Content-Type", "application/x-ndjson")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	encoder := json.NewEncoder(w)
+	for {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return
+			}
+			err := encoder.Encode(event)
+			if err != nil {
+				return
+			}
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+func respond(w http.ResponseWriter, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(value)
+}
@@ -3,6 +3,7 @@ package status
 import (
 	"fmt"
 	"sync"
+	"time"
 )
 
 type Type int
@@ -13,6 +14,8 @@ const (
 	Success
 	Failed
 	Errored
+	Skipped
+	Pending
 )
 
 func (t Type) String() string {
@@ -27,28 +30,106 @@ func (t Type) String() string {
 		return "failed"
 	case Errored:
 		return "errored"
+	case Skipped:
+		return "skipped"
+	case Pending:
+		return "pending"
 	}
 	return ""
 }
 
+func (t Type) ExitCode() int {
+	switch t {
+	case Success:
+		return 0
+	case Failed:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func Parse(name string) (Type, error) {
+	switch name {
+	case "unstarted":
+		return Unstarted, nil
+	case "running":
+		return Running, nil
+	case "success":
+		return Success, nil
+	case "failed":
+		return Failed, nil
+	case "errored":
+		return Errored, nil
+	case "skipped":
+		return Skipped, nil
+	case "pending":
+		return Pending, nil
+	}
+	return 0, fmt.Errorf("unknown status '%s'", name)
+}
+
+func (t Type) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+func (t *Type) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
 type Identifier interface {
 	ID() string
 }
 
 type currentState struct {
 	sync.Mutex
-	values map[string][]Type
+	*memo
+	values      map[string][]Type
+	attempts    map[string][]Attempt
+	subscribers []*subscriber
+	clock       func() time.Time
 }
 
 type Stater interface {
 	Get(task Identifier) []Type
 	Add(task Identifier, s Type) error
+	Watch() (<-chan Event, func())
+	Attempts(task Identifier) []Attempt
+	AddError(task Identifier, err error) error
 }
 
 func NewStatuses() Stater {
+	return NewStatusesWithClock(time.Now)
+}
+
+func NewStatusesWithClock(clock func() time.Time) Stater {
 	return &currentState{
-		values: map[string][]Type{},
+		memo:     newMemo(),
+		values:   map[string][]Type{},
+		attempts: map[string][]Attempt{},
+		clock:    clock,
+	}
+}
+
+func NewCopy(stater Stater, ids []string) Stater {
+	c := &currentState{
+		memo:     newMemo(),
+		values:   map[string][]Type{},
+		attempts: map[string][]Attempt{},
+		clock:    time.Now,
+	}
+	for _, id := range ids {
+		if statuses := stater.Get(identifier(id)); len(statuses) > 0 {
+			c.values[id] = statuses
+			c.attempts[id] = stater.Attempts(identifier(id))
+		}
 	}
+	return c
 }
 
 func (c *currentState) Add(task Identifier, s Type) error {
@@ -59,6 +140,7 @@ func (c *currentState) Add(task Identifier, s Type) error {
 	if !ok {
 		if s == Unstarted {
 			c.values[task.ID()] = append(c.values[task.ID()], s)
+			c.transitioned(task, 1, Unstarted, s)
 			return nil
 		}
 		return fmt.Errorf("the set status %s cannot be an initial Stater", s)
@@ -67,20 +149,55 @@ func (c *currentState) Add(task Identifier, s Type) error {
 	current := statuses[len(statuses)-1]
 	if current == Unstarted && s == Running {
 		c.values[task.ID()][len(statuses)-1] = s
+		c.transitioned(task, len(statuses), current, s)
+		return nil
+	}
+	if current == Running && s == Pending {
+		c.values[task.ID()][len(statuses)-1] = s
+		c.transitioned(task, len(statuses), current, s)
+		return nil
+	}
+	if current == Pending && (s == Running || finalState(s)) {
+		c.values[task.ID()][len(statuses)-1] = s
+		c.transitioned(task, len(statuses), current, s)
 		return nil
 	}
 	if current == Running && finalState(s) {
 		c.values[task.ID()][len(statuses)-1] = s
+		c.transitioned(task, len(statuses), current, s)
 		return nil
 	}
 	if finalState(current) && s == Unstarted {
 		c.values[task.ID()] = append(c.values[task.ID()], s)
+		c.transitioned(task, len(statuses)+1, current, s)
 		return nil
 	}
 
 	return fmt.Errorf("cannot transition from %s to %s", current, s)
 }
 
+func (c *currentState) transitioned(task Identifier, attempt int, from Type, to Type) {
+	now := c.clock()
+
+	attempts := c.attempts[task.ID()]
+	if len(attempts) < attempt {
+		attempts = append(attempts, Attempt{})
+	}
+	attempts[attempt-1].Transitions = append(attempts[attempt-1].Transitions, Transition{
+		Status: to,
+		At:     now,
+	})
+	c.attempts[task.ID()] = attempts
+
+	c.publish(Event{
+		TaskID:  task.ID(),
+		Attempt: attempt,
+		From:    from,
+		To:      to,
+		At:      now,
+	})
+}
+
 func finalState(s Type) bool {
 	return s == Failed || s == Success || s == Errored
 }
@@ -96,3 +213,35 @@ func (c *currentState) Get(task Identifier) []Type {
 	}
 	return []Type{}
 }
+
+func (c *currentState) Attempts(task Identifier) []Attempt {
+	c.Lock()
+	defer c.Unlock()
+
+	attempts := make([]Attempt, len(c.attempts[task.ID()]))
+	for i, attempt := range c.attempts[task.ID()] {
+		attempts[i] = Attempt{
+			Transitions: append([]Transition{}, attempt.Transitions...),
+			Error:       attempt.Error,
+			ExitCode:    attempt.ExitCode,
+		}
+	}
+	return attempts
+}
+
+func (c *currentState) AddError(task Identifier, err error) error {
+	c.Lock()
+	defer c.Unlock()
+
+	attempts := c.attempts[task.ID()]
+	if len(attempts) == 0 {
+		return fmt.Errorf("cannot add an error to %s before it has been queued", task.ID())
+	}
+
+	attempt := &attempts[len(attempts)-1]
+	attempt.Error = err.Error()
+	if code, ok := ExitCode(err); ok {
+		attempt.ExitCode = &code
+	}
+	return nil
+}
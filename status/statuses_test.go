@@ -86,6 +86,40 @@ var _ = Describe("Statuses", func() {
 		})
 	})
 
+	When("transitioning from pending", func() {
+		It("successfully transitions to running and final states", func() {
+			for _, s := range []Type{Running, Success, Failed, Errored} {
+				statuses := NewStatuses()
+				err := statuses.Add(task("A"), Unstarted)
+				Expect(err).ToNot(HaveOccurred())
+				err = statuses.Add(task("A"), Running)
+				Expect(err).ToNot(HaveOccurred())
+				err = statuses.Add(task("A"), Pending)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statuses.Get(task("A"))).To(Equal([]Type{Pending}))
+
+				err = statuses.Add(task("A"), s)
+				Expect(err).ToNot(HaveOccurred())
+				Expect(statuses.Get(task("A"))).To(Equal([]Type{s}))
+			}
+		})
+
+		It("fails transitioning to unstarted", func() {
+			statuses := NewStatuses()
+			err := statuses.Add(task("A"), Unstarted)
+			Expect(err).ToNot(HaveOccurred())
+			err = statuses.Add(task("A"), Pending)
+			Expect(err).To(HaveOccurred())
+			err = statuses.Add(task("A"), Running)
+			Expect(err).ToNot(HaveOccurred())
+			err = statuses.Add(task("A"), Pending)
+			Expect(err).ToNot(HaveOccurred())
+			err = statuses.Add(task("A"), Unstarted)
+			Expect(err).To(HaveOccurred())
+			Expect(statuses.Get(task("A"))).To(Equal([]Type{Pending}))
+		})
+	})
+
 	When("transitioning from failure", func() {
 		It("creates a new Stater when transitioning to unstarted", func() {
 			statuses := NewStatuses()
@@ -197,4 +231,41 @@ var _ = Describe("Statuses", func() {
 		}
 		Expect(statuses.Get(task("A"))).To(Equal([]Type{Success, Success, Success}))
 	})
+
+	It("maps final states to process exit codes", func() {
+		Expect(Success.ExitCode()).To(Equal(0))
+		Expect(Failed.ExitCode()).To(Equal(1))
+		Expect(Errored.ExitCode()).To(Equal(2))
+		Expect(Running.ExitCode()).To(Equal(2))
+	})
+
+	It("copies the statuses of the given tasks", func() {
+		statuses := NewStatuses()
+		for _, s := range []Type{Unstarted, Running, Failed} {
+			Expect(statuses.Add(task("A"), s)).To(Succeed())
+		}
+		Expect(statuses.AddError(task("A"), NewError("exit status 1", nil))).To(Succeed())
+		Expect(statuses.Add(task("B"), Unstarted)).To(Succeed())
+
+		copied := NewCopy(statuses, []string{"A"})
+		Expect(copied.Get(task("A"))).To(Equal([]Type{Failed}))
+		Expect(copied.Attempts(task("A"))).To(Equal(statuses.Attempts(task("A"))))
+		Expect(copied.Get(task("B"))).To(BeEmpty())
+
+		Expect(copied.Add(task("A"), Unstarted)).To(Succeed())
+		Expect(statuses.Get(task("A"))).To(Equal([]Type{Failed}))
+	})
+
+	It("remembers the first decision for a key", func() {
+		statuses := NewStatuses().(Memo)
+		_, ok := statuses.Recall("key")
+		Expect(ok).To(BeFalse())
+
+		Expect(statuses.Remember("key", true)).To(BeTrue())
+		Expect(statuses.Remember("key", false)).To(BeTrue())
+
+		held, ok := statuses.Recall("key")
+		Expect(ok).To(BeTrue())
+		Expect(held).To(BeTrue())
+	})
 })
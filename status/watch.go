@@ -0,0 +1,144 @@
+package status
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	watchBuffer  = 100
+	watchBacklog = 10000
+)
+
+type Event struct {
+	TaskID  string    `json:"task"`
+	Attempt int       `json:"attempt"`
+	From    Type      `json:"from"`
+	To      Type      `json:"to"`
+	At      time.Time `json:"at"`
+}
+
+type subscriber struct {
+	events  chan Event
+	wake    chan struct{}
+	done    chan struct{}
+	stopped chan struct{}
+
+	lock    sync.Mutex
+	pending []Event
+	once    sync.Once
+}
+
+func newSubscriber() *subscriber {
+	s := &subscriber{
+		events:  make(chan Event, watchBuffer),
+		wake:    make(chan struct{}, 1),
+		done:    make(chan struct{}),
+		stopped: make(chan struct{}),
+	}
+	go s.pump()
+	return s
+}
+
+func (s *subscriber) send(event Event) bool {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	if len(s.pending) == 0 {
+		select {
+		case s.events <- event:
+			return true
+		default:
+		}
+	}
+
+	if len(s.pending) >= watchBacklog {
+		s.pending = nil
+		s.stop()
+		return false
+	}
+
+	s.pending = append(s.pending, event)
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return true
+}
+
+func (s *subscriber) stop() {
+	s.once.Do(func() {
+		close(s.done)
+	})
+}
+
+func (s *subscriber) pump() {
+	defer close(s.stopped)
+	defer close(s.events)
+
+	for {
+		select {
+		case <-s.wake:
+		case <-s.done:
+			return
+		}
+
+		for {
+			s.lock.Lock()
+			if len(s.pending) == 0 {
+				s.lock.Unlock()
+				break
+			}
+			event := s.pending[0]
+			s.lock.Unlock()
+
+			select {
+			case s.events <- event:
+			case <-s.done:
+				return
+			}
+
+			s.lock.Lock()
+			if len(s.pending) > 0 {
+				s.pending = s.pending[1:]
+			}
+			s.lock.Unlock()
+		}
+	}
+}
+
+func (c *currentState) Watch() (<-chan Event, func()) {
+	c.Lock()
+	defer c.Unlock()
+
+	s := newSubscriber()
+	c.subscribers = append(c.subscribers, s)
+
+	return s.events, func() {
+		c.Lock()
+		c.unsubscribe(s)
+		c.Unlock()
+
+		s.stop()
+		<-s.stopped
+	}
+}
+
+func (c *currentState) unsubscribe(s *subscriber) {
+	for i, other := range c.subscribers {
+		if other == s {
+			c.subscribers = append(c.subscribers[:i], c.subscribers[i+1:]...)
+			return
+		}
+	}
+}
+
+func (c *currentState) publish(event Event) {
+	subscribers := c.subscribers[:0]
+	for _, s := range c.subscribers {
+		if s.send(event) {
+			subscribers = append(subscribers, s)
+		}
+	}
+	c.subscribers = subscribers
+}
@@ -0,0 +1,121 @@
+package status_test
+
+import (
+	"time"
+
+	. "github.com/jtarchie/dothings/status"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Watching statuses", func() {
+	transition := func(event Event) []interface{} {
+		return []interface{}{event.TaskID, event.Attempt, event.From, event.To}
+	}
+
+	It("delivers every transition to the subscriber", func() {
+		statuses := NewStatuses()
+		events, stop := statuses.Watch()
+		defer stop()
+
+		before := time.Now()
+		Expect(statuses.Add(task("A"), Unstarted)).To(Succeed())
+		Expect(statuses.Add(task("A"), Running)).To(Succeed())
+		Expect(statuses.Add(task("A"), Failed)).To(Succeed())
+		Expect(statuses.Add(task("A"), Unstarted)).To(Succeed())
+
+		var event Event
+		Expect(events).To(Receive(&event))
+		Expect(transition(event)).To(Equal([]interface{}{"A", 1, Unstarted, Unstarted}))
+		Expect(event.At).To(BeTemporally(">=", before))
+
+		Expect(events).To(Receive(&event))
+		Expect(transition(event)).To(Equal([]interface{}{"A", 1, Unstarted, Running}))
+
+		Expect(events).To(Receive(&event))
+		Expect(transition(event)).To(Equal([]interface{}{"A", 1, Running, Failed}))
+
+		Expect(events).To(Receive(&event))
+		Expect(transition(event)).To(Equal([]interface{}{"A", 2, Failed, Unstarted}))
+	})
+
+	It("does not deliver rejected transitions", func() {
+		statuses := NewStatuses()
+		events, stop := statuses.Watch()
+		defer stop()
+
+		Expect(statuses.Add(task("A"), Running)).NotTo(Succeed())
+		Expect(events).NotTo(Receive())
+	})
+
+	It("delivers to every subscriber", func() {
+		statuses := NewStatuses()
+		first, stopFirst := statuses.Watch()
+		defer stopFirst()
+		second, stopSecond := statuses.Watch()
+		defer stopSecond()
+
+		Expect(statuses.Add(task("A"), Unstarted)).To(Succeed())
+		Expect(first).To(Receive())
+		Expect(second).To(Receive())
+	})
+
+	It("closes the channel when stopped", func() {
+		statuses := NewStatuses()
+		events, stop := statuses.Watch()
+		stop()
+
+		Expect(events).To(BeClosed())
+		Expect(statuses.Add(task("A"), Unstarted)).To(Succeed())
+		stop()
+	})
+
+	It("queues events for subscribers that do not keep up", func() {
+		statuses := NewStatuses()
+		slow, stopSlow := statuses.Watch()
+		defer stopSlow()
+		fast, stopFast := statuses.Watch()
+		defer stopFast()
+
+		for i := 0; i < 100; i++ {
+			for _, s := range []Type{Unstarted, Running, Success} {
+				Expect(statuses.Add(task("A"), s)).To(Succeed())
+				Expect(fast).To(Receive())
+			}
+		}
+
+		for i := 0; i < 100; i++ {
+			for _, s := range []Type{Unstarted, Running, Success} {
+				var event Event
+				Eventually(slow).Should(Receive(&event))
+				Expect(event.Attempt).To(Equal(i + 1))
+				Expect(event.To).To(Equal(s))
+			}
+		}
+		Expect(slow).NotTo(BeClosed())
+		Expect(fast).NotTo(BeClosed())
+	})
+
+	It("disconnects subscribers that fall too far behind", func() {
+		statuses := NewStatuses()
+		stalled, stopStalled := statuses.Watch()
+		defer stopStalled()
+		fast, stopFast := statuses.Watch()
+		defer stopFast()
+
+		for i := 0; i < 4000; i++ {
+			for _, s := range []Type{Unstarted, Running, Success} {
+				Expect(statuses.Add(task("A"), s)).To(Succeed())
+				Expect(fast).To(Receive())
+			}
+		}
+
+		received := 0
+		for range stalled {
+			received++
+		}
+		Expect(received).To(BeNumerically(">", 0))
+		Expect(received).To(BeNumerically("<=", 10100))
+		Expect(fast).NotTo(BeClosed())
+	})
+})
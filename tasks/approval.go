@@ -0,0 +1,37 @@
+package tasks
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jtarchie/dothings/planner"
+
+	"github.com/jtarchie/dothings/status"
+)
+
+type Approval struct {
+	name    string
+	timeout time.Duration
+}
+
+var _ planner.Tasker = &Approval{}
+
+func NewApproval(name string, timeout time.Duration) *Approval {
+	return &Approval{
+		name:    name,
+		timeout: timeout,
+	}
+}
+
+func (a *Approval) ID() string {
+	return fmt.Sprintf("approval: %s", a.name)
+}
+
+func (a *Approval) ApprovalTimeout() time.Duration {
+	return a.timeout
+}
+
+func (a *Approval) Execute(io.Writer, io.Writer) (status.Type, error) {
+	return status.Errored, fmt.Errorf("approval '%s' can only be decided by an executor", a.name)
+}
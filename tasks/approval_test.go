@@ -0,0 +1,26 @@
+package tasks_test
+
+import (
+	"io/ioutil"
+	"time"
+
+	"github.com/jtarchie/dothings/status"
+	"github.com/jtarchie/dothings/tasks"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Approval", func() {
+	It("returns an ID based on the name", func() {
+		task := tasks.NewApproval("prod", time.Minute)
+		Expect(task.ID()).To(Equal("approval: prod"))
+		Expect(task.ApprovalTimeout()).To(Equal(time.Minute))
+	})
+
+	It("cannot be approved outside of an executor", func() {
+		task := tasks.NewApproval("prod", 0)
+		state, err := task.Execute(ioutil.Discard, ioutil.Discard)
+		Expect(state).To(Equal(status.Errored))
+		Expect(err).To(MatchError("approval 'prod' can only be decided by an executor"))
+	})
+})
@@ -1,6 +1,7 @@
 package tasks
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"os/exec"
@@ -30,13 +31,23 @@ func (c *LocalCommand) ID() string {
 }
 
 func (c *LocalCommand) Execute(stdout io.Writer, stderr io.Writer) (status.Type, error) {
-	command := exec.Command(c.command, c.args...)
+	return c.ExecuteContext(context.Background(), stdout, stderr)
+}
+
+func (c *LocalCommand) ExecuteContext(ctx context.Context, stdout io.Writer, stderr io.Writer) (status.Type, error) {
+	command := exec.CommandContext(ctx, c.command, c.args...)
 	command.Stdout = stdout
 	command.Stderr = stderr
 	err := command.Run()
 
 	if err != nil {
-		return status.Failed, nil
+		if ctx.Err() != nil {
+			return status.Failed, nil
+		}
+		if _, ok := err.(*exec.ExitError); ok {
+			return status.Failed, err
+		}
+		return status.Errored, fmt.Errorf("could not run command: %s", err)
 	}
 
 	return status.Success, nil
@@ -1,6 +1,8 @@
 package tasks_test
 
 import (
+	"context"
+
 	status2 "github.com/jtarchie/dothings/status"
 	"github.com/jtarchie/dothings/tasks"
 	. "github.com/onsi/ginkgo"
@@ -39,10 +41,35 @@ var _ = Describe("Command", func() {
 			Expect(status).To(Equal(status2.Failed))
 
 		})
+		It("returns the exit code when the program fails", func() {
+			task := tasks.NewCommand("bash", "-c", "exit 3")
+			status, err := task.Execute(GinkgoWriter, GinkgoWriter)
+			Expect(status).To(Equal(status2.Failed))
+			Expect(err).To(MatchError("exit status 3"))
+
+			code, ok := status2.ExitCode(err)
+			Expect(ok).To(BeTrue())
+			Expect(code).To(Equal(3))
+		})
+		It("errors when the program cannot be run", func() {
+			task := tasks.NewCommand("this-command-does-not-exist")
+			status, err := task.Execute(GinkgoWriter, GinkgoWriter)
+			Expect(status).To(Equal(status2.Errored))
+			Expect(err.Error()).To(HavePrefix("could not run command: "))
+		})
 		It("returns success when program exit code is 0", func() {
 			task := tasks.NewCommand("true")
 			status, _ := task.Execute(GinkgoWriter, GinkgoWriter)
 			Expect(status).To(Equal(status2.Success))
 		})
+
+		It("stops the program when the context is cancelled", func() {
+			task := tasks.NewCommand("sleep", "10")
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			status, _ := task.ExecuteContext(ctx, GinkgoWriter, GinkgoWriter)
+			Expect(status).To(Equal(status2.Failed))
+		})
 	})
 })